@@ -4,34 +4,87 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
-	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	AuthMethodToken             = "token"
+	AuthMethodPassword          = "password"
+	AuthMethodClientCredentials = "client_credentials"
 )
 
 type AuthConfig struct {
-	Username     string
-	Password     string
-	Environment  string
-	AuthToken    string
-	BaseURL      string
+	// AuthMethod selects the OAuth2 grant, password when empty.
+	AuthMethod   string
+	ClientID     string
+	ClientSecret string
+	Audience     string
+	// Scopes requested for the token. The password grant defaults to openid, profile and email.
+	Scopes []string
+	// OfflineAccess requests the offline_access scope so the password grant
+	// also returns a refresh token.
+	OfflineAccess bool
+	Username      string
+	Password      string
+	Environment   string
+	AuthToken     string
+	BaseURL       string
+	// IssuerURL enables OIDC discovery of the token endpoint. It takes
+	// precedence over BaseURL and Environment.
+	IssuerURL string
+	// ExpirySkew refreshes cached tokens this long before they expire, so a
+	// token does not lapse between GetToken and the request that uses it.
+	// It is capped at half the token lifetime.
+	ExpirySkew time.Duration
 }
 
+// DefaultTokenExpirySkew is the ExpirySkew used by the provider when none is configured.
+const DefaultTokenExpirySkew = 30 * time.Second
+
+const (
+	// defaultTokenLifetime is assumed when a token response omits expires_in.
+	defaultTokenLifetime = 5 * time.Minute
+	// authTimeout bounds a shared authentication, which no single caller can cancel.
+	authTimeout = 2 * time.Minute
+)
+
 type TokenResponse struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	ExpiresIn   int    `json:"expires_in"`
-	Scope       string `json:"scope"`
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	Scope        string `json:"scope"`
+	RefreshToken string `json:"refresh_token"`
 }
 
+// AuthClient caches the access token obtained with the configured
+// credentials. It is safe for concurrent use: Terraform runs resource
+// operations in parallel, and concurrent callers that find no valid token
+// share a single in-flight authentication.
 type AuthClient struct {
 	httpClient *http.Client
 	config     *AuthConfig
-	token      string
-	expiresAt  time.Time
+
+	mu           sync.Mutex
+	token        string
+	refreshAt    time.Time
+	refreshToken string
+	inflight     *authCall
+
+	discoveryMu        sync.Mutex
+	discoveredTokenURL string
+}
+
+// authCall is an authentication in progress that other callers can wait on.
+type authCall struct {
+	done  chan struct{}
+	token string
+	err   error
 }
 
 func NewAuthClient(config *AuthConfig) *AuthClient {
@@ -46,93 +99,257 @@ func (ac *AuthClient) GetToken(ctx context.Context) (string, error) {
 		return ac.config.AuthToken, nil
 	}
 
-	if ac.token != "" && time.Now().Before(ac.expiresAt) {
-		return ac.token, nil
+	ac.mu.Lock()
+	if ac.tokenValidLocked() {
+		token := ac.token
+		ac.mu.Unlock()
+		return token, nil
+	}
+
+	call := ac.inflight
+	if call == nil {
+		call = &authCall{done: make(chan struct{})}
+		ac.inflight = call
+		go ac.runAuthentication(ctx, call, ac.refreshToken)
+	}
+	ac.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// runAuthentication performs the shared authentication for call. It runs on
+// a context detached from the caller that started it, so cancelling that
+// caller does not fail the others waiting on the same call.
+func (ac *AuthClient) runAuthentication(ctx context.Context, call *authCall, refreshToken string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), authTimeout)
+	defer cancel()
+
+	tokenResp, err := ac.authenticate(ctx, refreshToken)
+
+	ac.mu.Lock()
+	if err == nil {
+		ac.token = tokenResp.AccessToken
+		ac.refreshAt = time.Now().Add(ac.tokenLifetime(tokenResp.ExpiresIn))
+		ac.refreshToken = tokenResp.RefreshToken
+		call.token = ac.token
+	}
+	call.err = err
+	ac.inflight = nil
+	ac.mu.Unlock()
+
+	close(call.done)
+}
+
+// tokenLifetime returns how long a token that expires in expiresIn seconds is
+// reused. ExpirySkew is capped at half the lifetime so short-lived tokens are
+// still cached, and a missing expires_in assumes defaultTokenLifetime.
+func (ac *AuthClient) tokenLifetime(expiresIn int) time.Duration {
+	lifetime := time.Duration(expiresIn) * time.Second
+	if lifetime <= 0 {
+		lifetime = defaultTokenLifetime
+	}
+
+	skew := ac.config.ExpirySkew
+	if skew > lifetime/2 {
+		skew = lifetime / 2
+	}
+	return lifetime - skew
+}
+
+func (ac *AuthClient) tokenValidLocked() bool {
+	return ac.token != "" && time.Now().Before(ac.refreshAt)
+}
+
+// authenticate obtains a new token, using the refresh token when one is held
+// and falling back to the configured credentials if it is rejected.
+func (ac *AuthClient) authenticate(ctx context.Context, refreshToken string) (*TokenResponse, error) {
+	if refreshToken != "" {
+		tokenResp, err := ac.authenticateWithRefreshToken(ctx, refreshToken)
+		if err == nil {
+			return tokenResp, nil
+		}
+		tflog.Debug(ctx, "Refresh token grant failed, falling back to credentials", map[string]interface{}{
+			"error": err.Error(),
+		})
 	}
 
 	return ac.authenticateWithCredentials(ctx)
 }
 
-func (ac *AuthClient) authenticateWithCredentials(ctx context.Context) (string, error) {
-	authURL := ac.getAuthURL()
-	
+func (ac *AuthClient) authenticateWithRefreshToken(ctx context.Context, refreshToken string) (*TokenResponse, error) {
+	formData := url.Values{}
+	formData.Set("grant_type", "refresh_token")
+	formData.Set("refresh_token", refreshToken)
+	formData.Set("client_id", ac.config.ClientID)
+	if ac.config.ClientSecret != "" {
+		formData.Set("client_secret", ac.config.ClientSecret)
+	}
+
+	tokenResp, err := ac.requestToken(ctx, formData)
+	if err != nil {
+		return nil, err
+	}
+
+	// Servers that do not rotate refresh tokens omit it from the response.
+	if tokenResp.RefreshToken == "" {
+		tokenResp.RefreshToken = refreshToken
+	}
+	return tokenResp, nil
+}
+
+// authenticateWithCredentials requests a token using only the AuthConfig, so
+// each provider instance authenticates against its own tenant.
+func (ac *AuthClient) authenticateWithCredentials(ctx context.Context) (*TokenResponse, error) {
 	formData := url.Values{}
-	formData.Set("grant_type", "password")
-	formData.Set("username", ac.config.Username)
-	formData.Set("password", ac.config.Password)
-	formData.Set("scope", "openid profile email")
-	config, err := LoadConfig()
+	switch ac.config.AuthMethod {
+	case AuthMethodClientCredentials:
+		formData.Set("grant_type", "client_credentials")
+		formData.Set("client_secret", ac.config.ClientSecret)
+	default:
+		formData.Set("grant_type", "password")
+		formData.Set("username", ac.config.Username)
+		formData.Set("password", ac.config.Password)
+	}
+
+	if scope := ac.scope(); scope != "" {
+		formData.Set("scope", scope)
+	}
+	if ac.config.Audience != "" {
+		formData.Set("audience", ac.config.Audience)
+	}
+	formData.Set("client_id", ac.config.ClientID)
+
+	return ac.requestToken(ctx, formData)
+}
+
+func (ac *AuthClient) requestToken(ctx context.Context, formData url.Values) (*TokenResponse, error) {
+	authURL, err := ac.getAuthURL(ctx)
 	if err != nil {
-		return "", fmt.Errorf("failed to load config: %v", err)
+		return nil, err
 	}
-	
-	formData.Set("audience", config.Audience)
-	formData.Set("client_id", config.ClientID)
 
 	req, err := http.NewRequestWithContext(ctx, "POST", authURL, strings.NewReader(formData.Encode()))
 	if err != nil {
-		return "", fmt.Errorf("failed to create auth request: %v", err)
+		return nil, fmt.Errorf("failed to create auth request: %v", err)
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("Accept", "application/json")
 
 	tflog.Debug(ctx, "Authenticating with credentials", map[string]interface{}{
-		"url":      authURL,
-		"username": ac.config.Username,
+		"url":        authURL,
+		"grant_type": formData.Get("grant_type"),
+		"username":   formData.Get("username"),
+		"client_id":  formData.Get("client_id"),
 	})
 
 	resp, err := ac.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to execute auth request: %v", err)
+		return nil, fmt.Errorf("failed to execute auth request: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("authentication failed with status: %d, body: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("authentication failed with status: %d, body: %s", resp.StatusCode, string(body))
 	}
 
 	var tokenResp TokenResponse
 	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
-		return "", fmt.Errorf("failed to decode token response: %v", err)
+		return nil, fmt.Errorf("failed to decode token response: %v", err)
 	}
 
-	ac.token = tokenResp.AccessToken
-	ac.expiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
-
 	tflog.Debug(ctx, "Authentication successful", map[string]interface{}{
-		"token_type": tokenResp.TokenType,
-		"expires_in": tokenResp.ExpiresIn,
+		"token_type":    tokenResp.TokenType,
+		"expires_in":    tokenResp.ExpiresIn,
+		"refresh_token": tokenResp.RefreshToken != "",
 	})
 
-	return ac.token, nil
+	return &tokenResp, nil
+}
+
+func (ac *AuthClient) scope() string {
+	scopes := ac.config.Scopes
+	if len(scopes) == 0 && ac.config.AuthMethod != AuthMethodClientCredentials {
+		scopes = []string{"openid", "profile", "email"}
+	}
+
+	if ac.config.OfflineAccess && !containsString(scopes, "offline_access") {
+		scopes = append(append([]string{}, scopes...), "offline_access")
+	}
+	return strings.Join(scopes, " ")
 }
 
-func (ac *AuthClient) getAuthURL() string {
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (ac *AuthClient) getAuthURL(ctx context.Context) (string, error) {
+	if ac.config.IssuerURL != "" {
+		return ac.tokenEndpoint(ctx)
+	}
+
 	baseURL := ac.config.BaseURL
 	if baseURL == "" {
 		switch ac.config.Environment {
-		case "qa":
+		case "", "qa":
 			baseURL = "https://pace-app-qa.us.auth0.com"
 		case "staging":
 			baseURL = "https://pace-app-staging.us.auth0.com"
 		case "prod":
 			baseURL = "https://pace-app.us.auth0.com"
 		default:
-			baseURL = "https://pace-app-qa.us.auth0.com"
+			// A custom profile has no built-in tenant; falling back to another
+			// environment's would send its credentials to the wrong server.
+			return "", fmt.Errorf("environment %q has no auth_url or issuer_url and no built-in tenant", ac.config.Environment)
 		}
 	}
-	return fmt.Sprintf("%s/oauth/token", baseURL)
+	return fmt.Sprintf("%s/oauth/token", baseURL), nil
+}
+
+// UsesStaticToken reports whether a fixed auth token is configured, in which
+// case there is nothing to refresh.
+func (ac *AuthClient) UsesStaticToken() bool {
+	return ac.config.AuthToken != ""
 }
 
 func (ac *AuthClient) IsTokenValid() bool {
-	return ac.token != "" && time.Now().Before(ac.expiresAt)
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	return ac.tokenValidLocked()
 }
 
+// RefreshToken discards the cached access token and obtains a new one, using
+// the refresh token when the server issued one.
 func (ac *AuthClient) RefreshToken(ctx context.Context) error {
+	ac.mu.Lock()
 	ac.token = ""
-	ac.expiresAt = time.Time{}
+	ac.refreshAt = time.Time{}
+	ac.mu.Unlock()
+
 	_, err := ac.GetToken(ctx)
 	return err
 }
+
+// InvalidateToken discards the cached token if it is still the given one.
+// Callers that saw the same rejected token therefore trigger a single
+// re-authentication instead of each discarding the other's fresh token.
+func (ac *AuthClient) InvalidateToken(token string) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	if ac.token == token {
+		ac.token = ""
+		ac.refreshAt = time.Time{}
+	}
+}
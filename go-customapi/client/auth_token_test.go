@@ -0,0 +1,277 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testTokenServer serves /oauth/token, blocking each request until release
+// is closed, and counts the token requests it receives.
+func testTokenServer(t *testing.T, expiresIn int, release <-chan struct{}) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token": "token-%d", "expires_in": %d}`, n, expiresIn)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func TestGetTokenSingleFlight(t *testing.T) {
+	release := make(chan struct{})
+	server, calls := testTokenServer(t, 3600, release)
+	ac := NewAuthClient(&AuthConfig{Username: "user", Password: "pass", BaseURL: server.URL})
+
+	const callers = 20
+	tokens := make([]string, callers)
+	errs := make([]error, callers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], errs[i] = ac.GetToken(context.Background())
+		}(i)
+	}
+
+	waitForCalls(t, calls, 1)
+	close(release)
+	wg.Wait()
+
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil || tokens[i] != "token-1" {
+			t.Errorf("caller %d got %q, %v", i, tokens[i], errs[i])
+		}
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("token calls = %d, want 1", got)
+	}
+}
+
+func TestGetTokenLeaderCancelled(t *testing.T) {
+	release := make(chan struct{})
+	server, calls := testTokenServer(t, 3600, release)
+	ac := NewAuthClient(&AuthConfig{Username: "user", Password: "pass", BaseURL: server.URL})
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := ac.GetToken(leaderCtx)
+		leaderErr <- err
+	}()
+	waitForCalls(t, calls, 1)
+
+	waiter := make(chan string, 1)
+	go func() {
+		token, err := ac.GetToken(context.Background())
+		if err != nil {
+			t.Errorf("waiter: %v", err)
+		}
+		waiter <- token
+	}()
+
+	cancel()
+	if err := <-leaderErr; err != context.Canceled {
+		t.Errorf("leader error = %v, want %v", err, context.Canceled)
+	}
+
+	close(release)
+	if token := <-waiter; token != "token-1" {
+		t.Errorf("waiter token = %q, want token-1", token)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("token calls = %d, want 1", got)
+	}
+}
+
+func TestGetTokenShortLifetimeIsCached(t *testing.T) {
+	for _, expiresIn := range []int{0, 10, 30} {
+		t.Run(fmt.Sprintf("expires_in %d", expiresIn), func(t *testing.T) {
+			release := make(chan struct{})
+			close(release)
+			server, calls := testTokenServer(t, expiresIn, release)
+			ac := NewAuthClient(&AuthConfig{Username: "user", Password: "pass", BaseURL: server.URL, ExpirySkew: DefaultTokenExpirySkew})
+
+			for i := 0; i < 3; i++ {
+				if _, err := ac.GetToken(context.Background()); err != nil {
+					t.Fatalf("GetToken: %v", err)
+				}
+			}
+			if got := atomic.LoadInt32(calls); got != 1 {
+				t.Errorf("token calls = %d, want 1", got)
+			}
+		})
+	}
+}
+
+func TestTokenLifetime(t *testing.T) {
+	cases := []struct {
+		name      string
+		skew      time.Duration
+		expiresIn int
+		want      time.Duration
+	}{
+		{name: "skew applied", skew: 30 * time.Second, expiresIn: 3600, want: 3570 * time.Second},
+		{name: "skew capped at half", skew: 30 * time.Second, expiresIn: 40, want: 20 * time.Second},
+		{name: "skew equal to lifetime", skew: 30 * time.Second, expiresIn: 30, want: 15 * time.Second},
+		{name: "missing expires_in", skew: 30 * time.Second, expiresIn: 0, want: defaultTokenLifetime - 30*time.Second},
+		{name: "no skew", expiresIn: 60, want: 60 * time.Second},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := NewAuthClient(&AuthConfig{ExpirySkew: tc.skew})
+			if got := ac.tokenLifetime(tc.expiresIn); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetAuthURL(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  AuthConfig
+		want    string
+		wantErr bool
+	}{
+		{name: "auth url", config: AuthConfig{BaseURL: "https://auth.example.com", Environment: "dev"}, want: "https://auth.example.com/oauth/token"},
+		{name: "unset environment", want: "https://pace-app-qa.us.auth0.com/oauth/token"},
+		{name: "staging", config: AuthConfig{Environment: "staging"}, want: "https://pace-app-staging.us.auth0.com/oauth/token"},
+		{name: "prod", config: AuthConfig{Environment: "prod"}, want: "https://pace-app.us.auth0.com/oauth/token"},
+		{name: "custom profile", config: AuthConfig{Environment: "dev"}, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NewAuthClient(&tc.config).getAuthURL(context.Background())
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetTokenRefreshTokenGrant(t *testing.T) {
+	cases := []struct {
+		name        string
+		rejectGrant bool
+		rotate      bool
+		wantGrants  []string
+		wantRefresh []string
+	}{
+		{
+			name:        "refresh grant succeeds",
+			rotate:      true,
+			wantGrants:  []string{"password", "refresh_token", "refresh_token"},
+			wantRefresh: []string{"refresh-1", "refresh-2"},
+		},
+		{
+			name:        "refresh token kept when not rotated",
+			wantGrants:  []string{"password", "refresh_token", "refresh_token"},
+			wantRefresh: []string{"refresh-1", "refresh-1"},
+		},
+		{
+			name:        "rejected refresh falls back to password",
+			rejectGrant: true,
+			wantGrants:  []string{"password", "refresh_token", "password", "refresh_token", "password"},
+			wantRefresh: []string{"refresh-1", "refresh-3"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var grants, refreshTokens, scopes []string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					t.Errorf("ParseForm: %v", err)
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				grant := r.PostForm.Get("grant_type")
+				grants = append(grants, grant)
+				n := len(grants)
+
+				switch grant {
+				case "refresh_token":
+					refreshTokens = append(refreshTokens, r.PostForm.Get("refresh_token"))
+					if tc.rejectGrant {
+						http.Error(w, `{"error": "invalid_grant"}`, http.StatusBadRequest)
+						return
+					}
+					if !tc.rotate {
+						fmt.Fprintf(w, `{"access_token": "token-%d", "expires_in": 3600}`, n)
+						return
+					}
+				case "password":
+					scopes = append(scopes, r.PostForm.Get("scope"))
+				}
+				fmt.Fprintf(w, `{"access_token": "token-%d", "expires_in": 3600, "refresh_token": "refresh-%d"}`, n, n)
+			}))
+			t.Cleanup(server.Close)
+
+			ac := NewAuthClient(&AuthConfig{
+				Username:      "user",
+				Password:      "pass",
+				ClientID:      "id",
+				OfflineAccess: true,
+				BaseURL:       server.URL,
+			})
+
+			for i := 0; i < 3; i++ {
+				token, err := ac.GetToken(context.Background())
+				if err != nil {
+					t.Fatalf("GetToken: %v", err)
+				}
+				ac.InvalidateToken(token)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if fmt.Sprint(grants) != fmt.Sprint(tc.wantGrants) {
+				t.Errorf("grants = %v, want %v", grants, tc.wantGrants)
+			}
+			if fmt.Sprint(refreshTokens) != fmt.Sprint(tc.wantRefresh) {
+				t.Errorf("refresh tokens sent = %v, want %v", refreshTokens, tc.wantRefresh)
+			}
+			for _, scope := range scopes {
+				if !strings.Contains(scope, "offline_access") {
+					t.Errorf("password grant scope %q does not request offline_access", scope)
+				}
+			}
+		})
+	}
+}
+
+func waitForCalls(t *testing.T, calls *int32, want int32) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(calls) < want {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d token calls", want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -1,52 +1,76 @@
 package client
 
 import (
-	"os"
+	"fmt"
 	"github.com/joho/godotenv"
+	"os"
 )
 
 type Config struct {
-	BaseURL           string
-	AuthURL           string
-	Environment       string
-	DefaultOrgID      string
-	ClientID          string
-	Audience          string
-	Username          string
-	Password          string
-	AuthToken         string
+	BaseURL      string
+	AuthURL      string
+	IssuerURL    string
+	Environment  string
+	Profile      string
+	DefaultOrgID string
+	ClientID     string
+	Audience     string
+	Username     string
+	Password     string
+	AuthToken    string
+	ClientSecret string
 }
 
+// LoadConfig reads the configuration from the CUSTOMAPI_ environment
+// variables. It does not read any .env file; use LoadConfigFile for that.
 func LoadConfig() (*Config, error) {
-	// Try to load .env file if it exists
-	_ = godotenv.Load()
-
-	config := &Config{
-		BaseURL:      getEnvOrDefault("CUSTOMAPI_BASE_URL"),
-		AuthURL:      getEnvOrDefault("CUSTOMAPI_AUTH_URL"),
-		Environment:  getEnvOrDefault("CUSTOMAPI_ENVIRONMENT"),
-		DefaultOrgID: getEnvOrDefault("CUSTOMAPI_ORG_ID"),
-		ClientID:     getEnvOrDefault("CUSTOMAPI_CLIENT_ID"),
-		Audience:     getEnvOrDefault("CUSTOMAPI_AUDIENCE"),
-		Username:     getEnvOrDefault("CUSTOMAPI_USERNAME"),
-		Password:     getEnvOrDefault("CUSTOMAPI_PASSWORD"),
-		AuthToken:    getEnvOrDefault("CUSTOMAPI_AUTH_TOKEN"),
+	return configFromLookup(getEnvOrDefault), nil
+}
+
+// LoadConfigFile reads the configuration from a dotenv file without
+// modifying the process environment.
+func LoadConfigFile(path string) (*Config, error) {
+	values, err := godotenv.Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
 	}
 
-	return config, nil
+	return configFromLookup(func(key string) string {
+		return values[key]
+	}), nil
+}
+
+func configFromLookup(lookup func(key string) string) *Config {
+	return &Config{
+		BaseURL:      lookup("CUSTOMAPI_BASE_URL"),
+		AuthURL:      lookup("CUSTOMAPI_AUTH_URL"),
+		IssuerURL:    lookup("CUSTOMAPI_ISSUER_URL"),
+		Environment:  lookup("CUSTOMAPI_ENVIRONMENT"),
+		Profile:      lookup("CUSTOMAPI_PROFILE"),
+		DefaultOrgID: lookup("CUSTOMAPI_ORG_ID"),
+		ClientID:     lookup("CUSTOMAPI_CLIENT_ID"),
+		Audience:     lookup("CUSTOMAPI_AUDIENCE"),
+		Username:     lookup("CUSTOMAPI_USERNAME"),
+		Password:     lookup("CUSTOMAPI_PASSWORD"),
+		AuthToken:    lookup("CUSTOMAPI_AUTH_TOKEN"),
+		ClientSecret: lookup("CUSTOMAPI_CLIENT_SECRET"),
+	}
 }
 
 func getEnvOrDefault(key string) string {
 	return os.Getenv(key)
 }
 
-
 func (c *Config) GetAuthConfig() *AuthConfig {
 	return &AuthConfig{
-		Username:    c.Username,
-		Password:    c.Password,
-		AuthToken:   c.AuthToken,
-		Environment: c.Environment,
-		BaseURL:     c.AuthURL,
+		Username:     c.Username,
+		Password:     c.Password,
+		AuthToken:    c.AuthToken,
+		Environment:  c.Environment,
+		BaseURL:      c.AuthURL,
+		IssuerURL:    c.IssuerURL,
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		Audience:     c.Audience,
 	}
 }
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "staging.env")
+	content := "CUSTOMAPI_USERNAME=file-user\nCUSTOMAPI_PASSWORD=\"p#ss word\"\nCUSTOMAPI_PROFILE=staging\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write env file: %v", err)
+	}
+
+	t.Setenv("CUSTOMAPI_USERNAME", "env-user")
+
+	config, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile: %v", err)
+	}
+
+	if config.Username != "file-user" || config.Password != "p#ss word" || config.Profile != "staging" {
+		t.Errorf("unexpected config %+v", config)
+	}
+	if got := os.Getenv("CUSTOMAPI_USERNAME"); got != "env-user" {
+		t.Errorf("process environment modified: CUSTOMAPI_USERNAME = %q", got)
+	}
+	if _, ok := os.LookupEnv("CUSTOMAPI_PROFILE"); ok {
+		t.Errorf("process environment modified: CUSTOMAPI_PROFILE set")
+	}
+}
+
+func TestLoadConfigFileMissing(t *testing.T) {
+	if _, err := LoadConfigFile(filepath.Join(t.TempDir(), "missing.env")); err == nil {
+		t.Error("expected an error for a missing env file")
+	}
+}
+
+func TestLoadConfigIgnoresDotEnv(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("CUSTOMAPI_USERNAME=dotenv-user\nCUSTOMAPI_AUTH_TOKEN=dotenv-token\n"), 0o600); err != nil {
+		t.Fatalf("write .env: %v", err)
+	}
+	t.Chdir(dir)
+	t.Setenv("CUSTOMAPI_USERNAME", "")
+	t.Setenv("CUSTOMAPI_AUTH_TOKEN", "")
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	if config.Username != "" || config.AuthToken != "" {
+		t.Errorf(".env in the working directory was read: %+v", config)
+	}
+	if got := os.Getenv("CUSTOMAPI_AUTH_TOKEN"); got != "" {
+		t.Errorf("process environment modified: CUSTOMAPI_AUTH_TOKEN = %q", got)
+	}
+}
@@ -4,51 +4,80 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"io"
 	"net/http"
 	"net/url"
-	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strings"
 	"terraform-provider-customapi/go-customapi/client/types"
 )
 
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+
+	// OrganizationHeader selects the organization a request acts on.
+	OrganizationHeader = "current-organization"
+)
+
 type CustomAPIClient struct {
 	*Client
+	expectedStatusCodes []int
+	retry               RetryConfig
+	defaultOrgID        string
 }
 
 func NewCustomAPIClient(authConfig *AuthConfig, baseURL string) *CustomAPIClient {
 	return &CustomAPIClient{
 		Client: NewClient(authConfig, baseURL),
+		retry:  DefaultRetryConfig(),
 	}
 }
 
 func (c *CustomAPIClient) MakeRequest(ctx context.Context, req *types.CustomAPIRequest) (*types.CustomAPIResponse, error) {
+	fullURL, err := c.buildURL(req.URL, req.QueryParams)
+	if err != nil {
+		return nil, err
+	}
+
 	token, err := c.GetToken(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get auth token: %v", err)
 	}
 
-	fullURL := c.buildURL(req.URL, req.QueryParams)
-	
 	var requestData interface{}
 	if len(req.Body) > 0 {
 		requestData = req.Body
 	}
 
-	httpReq, err := createRequest(ctx, req.Method, fullURL, requestData)
+	resp, err := c.executeWithRetry(ctx, req, fullURL, requestData, token)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+		return nil, err
 	}
 
-	c.setHeaders(httpReq, req.Headers, token)
+	// A 401 usually means the cached token was revoked or rotated before its
+	// expiry. Re-authenticate once and replay; a second 401 is returned as is.
+	// A static token has nothing to refresh, and a request that expects 401
+	// gets the response unchanged.
+	if resp.StatusCode == http.StatusUnauthorized && !c.authClient.UsesStaticToken() && !c.isExpectedStatus(req, resp.StatusCode) {
+		resp.Body.Close()
 
-	tflog.Debug(ctx, "Making API request", map[string]interface{}{
-		"method": req.Method,
-		"url":    fullURL,
-		"headers": httpReq.Header,
-	})
+		tflog.Info(ctx, "Received 401, refreshing auth token and replaying request", map[string]interface{}{
+			"method": req.Method,
+			"url":    fullURL,
+		})
 
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %v", err)
+		c.authClient.InvalidateToken(token)
+
+		token, err = c.GetToken(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to refresh auth token after 401: %v", err)
+		}
+
+		resp, err = c.executeWithRetry(ctx, req, fullURL, requestData, token)
+		if err != nil {
+			return nil, err
+		}
 	}
 	defer resp.Body.Close()
 
@@ -64,11 +93,19 @@ func (c *CustomAPIClient) MakeRequest(ctx context.Context, req *types.CustomAPIR
 		StatusCode: resp.StatusCode,
 		Headers:    responseHeaders,
 		Body:       []byte(responseBody),
-		Success:    resp.StatusCode >= 200 && resp.StatusCode < 300,
+		Success:    c.isExpectedStatus(req, resp.StatusCode),
+	}
+
+	if envelope, ok := types.ParseAPIResponse(apiResponse.Body); ok {
+		apiResponse.Envelope = envelope
 	}
 
 	if !apiResponse.Success {
-		apiResponse.Error = fmt.Sprintf("Request failed with status %d", resp.StatusCode)
+		apiResponse.APIError = types.NewAPIError(resp.StatusCode, apiResponse.Envelope)
+		if resp.StatusCode == http.StatusUnauthorized && c.authClient.UsesStaticToken() && apiResponse.APIError.Details == "" {
+			apiResponse.APIError.Details = "the configured auth_token is expired or revoked: provide a new auth_token or use username and password"
+		}
+		apiResponse.Error = apiResponse.APIError.Error()
 	}
 
 	tflog.Debug(ctx, "API response received", map[string]interface{}{
@@ -79,6 +116,94 @@ func (c *CustomAPIClient) MakeRequest(ctx context.Context, req *types.CustomAPIR
 	return apiResponse, nil
 }
 
+// executeWithRetry sends the request, retrying transient failures according
+// to the client's RetryConfig. The request is rebuilt for every attempt so the
+// body can be replayed.
+func (c *CustomAPIClient) executeWithRetry(ctx context.Context, req *types.CustomAPIRequest, fullURL string, requestData interface{}, token string) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		httpReq, err := createRequest(ctx, req.Method, fullURL, requestData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %v", err)
+		}
+
+		c.setHeaders(httpReq, req.Headers, token)
+
+		tflog.Debug(ctx, "Making API request", map[string]interface{}{
+			"method":  req.Method,
+			"url":     fullURL,
+			"headers": httpReq.Header,
+			"attempt": attempt,
+		})
+
+		resp, err := c.httpClient.Do(httpReq)
+		if !c.retry.shouldRetry(req.Method, attempt, resp, err) {
+			if err != nil {
+				return nil, fmt.Errorf("failed to execute request: %v", err)
+			}
+			return resp, nil
+		}
+
+		wait := c.retry.backoff(attempt, resp)
+		fields := map[string]interface{}{
+			"method":  req.Method,
+			"url":     fullURL,
+			"attempt": attempt,
+			"wait":    wait.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status_code"] = resp.StatusCode
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		tflog.Warn(ctx, "Retrying API request", fields)
+
+		if err := sleepWithContext(ctx, wait); err != nil {
+			return nil, fmt.Errorf("request retry cancelled: %v", err)
+		}
+	}
+}
+
+// SetRetryConfig replaces the retry behaviour used by MakeRequest.
+func (c *CustomAPIClient) SetRetryConfig(retry RetryConfig) {
+	c.retry = retry
+}
+
+// SetExpectedStatusCodes sets the status codes treated as success for requests
+// that do not specify their own. An empty list means any 2xx status.
+func (c *CustomAPIClient) SetExpectedStatusCodes(codes []int) {
+	c.expectedStatusCodes = codes
+}
+
+// SetDefaultOrgID sets the organization sent in the current-organization
+// header when a request does not set the header itself.
+func (c *CustomAPIClient) SetDefaultOrgID(orgID string) {
+	c.defaultOrgID = orgID
+}
+
+func (c *CustomAPIClient) DefaultOrgID() string {
+	return c.defaultOrgID
+}
+
+func (c *CustomAPIClient) isExpectedStatus(req *types.CustomAPIRequest, statusCode int) bool {
+	expected := req.ExpectedStatusCodes
+	if len(expected) == 0 {
+		expected = c.expectedStatusCodes
+	}
+
+	if len(expected) == 0 {
+		return statusCode >= 200 && statusCode < 300
+	}
+
+	for _, code := range expected {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *CustomAPIClient) GetUserProfile(ctx context.Context, orgID string) (*types.UserProfile, error) {
 	req := &types.CustomAPIRequest{
 		Method: "GET",
@@ -89,7 +214,7 @@ func (c *CustomAPIClient) GetUserProfile(ctx context.Context, orgID string) (*ty
 	}
 
 	if orgID != "" {
-		req.Headers["current-organization"] = orgID
+		req.Headers[OrganizationHeader] = orgID
 	}
 
 	resp, err := c.MakeRequest(ctx, req)
@@ -109,21 +234,16 @@ func (c *CustomAPIClient) GetUserProfile(ctx context.Context, orgID string) (*ty
 	return &profile, nil
 }
 
-func (c *CustomAPIClient) buildURL(endpoint string, queryParams map[string]string) string {
+func (c *CustomAPIClient) buildURL(endpoint string, queryParams map[string]string) (string, error) {
 	baseURL := c.GetBaseURL()
-	if baseURL == "" {
-		config, err := LoadConfig()
-		if err != nil {
-			panic("CUSTOMAPI_BASE_URL environment variable is required")
-		}
-		baseURL = config.BaseURL
-		if baseURL == "" {
-			panic("CUSTOMAPI_BASE_URL environment variable is required")
-		}
+	if strings.HasPrefix(endpoint, "http://") || strings.HasPrefix(endpoint, "https://") {
+		baseURL = ""
+	} else if baseURL == "" {
+		return "", fmt.Errorf("no base URL configured for endpoint %q: set base_url or CUSTOMAPI_BASE_URL, or use an absolute URL", endpoint)
 	}
 
 	fullURL := baseURL + endpoint
-	
+
 	if len(queryParams) > 0 {
 		params := url.Values{}
 		for key, value := range queryParams {
@@ -132,7 +252,7 @@ func (c *CustomAPIClient) buildURL(endpoint string, queryParams map[string]strin
 		fullURL += "?" + params.Encode()
 	}
 
-	return fullURL
+	return fullURL, nil
 }
 
 func (c *CustomAPIClient) setHeaders(req *http.Request, customHeaders map[string]string, token string) {
@@ -141,11 +261,19 @@ func (c *CustomAPIClient) setHeaders(req *http.Request, customHeaders map[string
 	req.Header.Set("Accept", "application/json, text/plain, */*")
 	req.Header.Set("User-Agent", "Terraform-Provider-CustomAPI/1.0")
 
+	if c.defaultOrgID != "" {
+		req.Header.Set(OrganizationHeader, c.defaultOrgID)
+	}
+
 	for key, value := range customHeaders {
 		req.Header.Set(key, value)
 	}
-}
 
+	// An empty organization overrides the default and sends no header at all.
+	if req.Header.Get(OrganizationHeader) == "" {
+		req.Header.Del(OrganizationHeader)
+	}
+}
 
 func (c *CustomAPIClient) CreateResource(ctx context.Context, endpoint string, data interface{}, orgID string) (*types.CustomAPIResponse, error) {
 	body, err := json.Marshal(data)
@@ -163,7 +291,7 @@ func (c *CustomAPIClient) CreateResource(ctx context.Context, endpoint string, d
 	}
 
 	if orgID != "" {
-		req.Headers["current-organization"] = orgID
+		req.Headers[OrganizationHeader] = orgID
 	}
 
 	return c.MakeRequest(ctx, req)
@@ -179,7 +307,7 @@ func (c *CustomAPIClient) ReadResource(ctx context.Context, endpoint string, org
 	}
 
 	if orgID != "" {
-		req.Headers["current-organization"] = orgID
+		req.Headers[OrganizationHeader] = orgID
 	}
 
 	return c.MakeRequest(ctx, req)
@@ -201,7 +329,7 @@ func (c *CustomAPIClient) UpdateResource(ctx context.Context, endpoint string, d
 	}
 
 	if orgID != "" {
-		req.Headers["current-organization"] = orgID
+		req.Headers[OrganizationHeader] = orgID
 	}
 
 	return c.MakeRequest(ctx, req)
@@ -217,7 +345,7 @@ func (c *CustomAPIClient) DeleteResource(ctx context.Context, endpoint string, o
 	}
 
 	if orgID != "" {
-		req.Headers["current-organization"] = orgID
+		req.Headers[OrganizationHeader] = orgID
 	}
 
 	return c.MakeRequest(ctx, req)
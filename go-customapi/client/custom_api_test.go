@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"terraform-provider-customapi/go-customapi/client/types"
+)
+
+// testAPIServer serves /oauth/token with numbered tokens and /api/item with
+// the status returned by status for the presented token.
+func testAPIServer(t *testing.T, status func(token string) int) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var tokenCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/token":
+			n := atomic.AddInt32(&tokenCalls, 1)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"access_token": "token-%d", "expires_in": 3600}`, n)
+		case "/api/item":
+			w.WriteHeader(status(r.Header.Get("Authorization")))
+			fmt.Fprint(w, `{}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &tokenCalls
+}
+
+func TestMakeRequestUnauthorized(t *testing.T) {
+	rejectFirstToken := func(token string) int {
+		if token == "Bearer token-1" {
+			return http.StatusUnauthorized
+		}
+		return http.StatusOK
+	}
+	alwaysUnauthorized := func(string) int { return http.StatusUnauthorized }
+
+	cases := []struct {
+		name           string
+		static         bool
+		status         func(string) int
+		expected       []int
+		wantStatus     int
+		wantSuccess    bool
+		wantTokenCalls int32
+	}{
+		{name: "refreshes and replays", status: rejectFirstToken, wantStatus: 200, wantSuccess: true, wantTokenCalls: 2},
+		{name: "second 401 returned", status: alwaysUnauthorized, wantStatus: 401, wantTokenCalls: 2},
+		{name: "expected 401 not replayed", status: alwaysUnauthorized, expected: []int{401}, wantStatus: 401, wantSuccess: true, wantTokenCalls: 1},
+		{name: "static token returns response", static: true, status: alwaysUnauthorized, wantStatus: 401},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, tokenCalls := testAPIServer(t, tc.status)
+
+			authConfig := &AuthConfig{Username: "user", Password: "pass", BaseURL: server.URL}
+			if tc.static {
+				authConfig = &AuthConfig{AuthToken: "static"}
+			}
+			c := NewCustomAPIClient(authConfig, server.URL)
+
+			resp, err := c.MakeRequest(context.Background(), &types.CustomAPIRequest{
+				Method:              http.MethodGet,
+				URL:                 "/api/item",
+				ExpectedStatusCodes: tc.expected,
+			})
+			if err != nil {
+				t.Fatalf("MakeRequest: %v", err)
+			}
+
+			if resp.StatusCode != tc.wantStatus || resp.Success != tc.wantSuccess {
+				t.Errorf("status %d success %v, want %d %v", resp.StatusCode, resp.Success, tc.wantStatus, tc.wantSuccess)
+			}
+			if got := atomic.LoadInt32(tokenCalls); got != tc.wantTokenCalls {
+				t.Errorf("token calls = %d, want %d", got, tc.wantTokenCalls)
+			}
+			if tc.static && (resp.APIError == nil || resp.APIError.Details == "") {
+				t.Errorf("expected a hint about the static token, got %#v", resp.APIError)
+			}
+		})
+	}
+}
+
+func TestMakeRequestOrganizationHeader(t *testing.T) {
+	cases := []struct {
+		name      string
+		headers   map[string]string
+		want      string
+		wantFound bool
+	}{
+		{name: "provider default", want: "default-org", wantFound: true},
+		{name: "override", headers: map[string]string{OrganizationHeader: "other-org"}, want: "other-org", wantFound: true},
+		{name: "empty suppresses header", headers: map[string]string{OrganizationHeader: ""}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Values(OrganizationHeader)
+				fmt.Fprint(w, `{}`)
+			}))
+			t.Cleanup(server.Close)
+
+			c := NewCustomAPIClient(&AuthConfig{AuthToken: "token"}, server.URL)
+			c.SetDefaultOrgID("default-org")
+
+			if _, err := c.MakeRequest(context.Background(), &types.CustomAPIRequest{
+				Method:  http.MethodGet,
+				URL:     "/api/item",
+				Headers: tc.headers,
+			}); err != nil {
+				t.Fatalf("MakeRequest: %v", err)
+			}
+
+			if tc.wantFound != (len(got) > 0) || (tc.wantFound && got[0] != tc.want) {
+				t.Errorf("%s header = %q, want %q (sent %v)", OrganizationHeader, got, tc.want, tc.wantFound)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const openIDConfigurationPath = "/.well-known/openid-configuration"
+
+// OpenIDConfiguration is the subset of the OIDC discovery document used by the client.
+type OpenIDConfiguration struct {
+	Issuer        string `json:"issuer"`
+	TokenEndpoint string `json:"token_endpoint"`
+}
+
+// tokenEndpoint returns the token endpoint advertised by the issuer. The
+// discovery document is fetched once per AuthClient; failures are not cached
+// so the next authentication retries the lookup.
+func (ac *AuthClient) tokenEndpoint(ctx context.Context) (string, error) {
+	ac.discoveryMu.Lock()
+	defer ac.discoveryMu.Unlock()
+
+	if ac.discoveredTokenURL != "" {
+		return ac.discoveredTokenURL, nil
+	}
+
+	discovery, err := ac.discoverOpenIDConfiguration(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	ac.discoveredTokenURL = discovery.TokenEndpoint
+	return ac.discoveredTokenURL, nil
+}
+
+func (ac *AuthClient) discoverOpenIDConfiguration(ctx context.Context) (*OpenIDConfiguration, error) {
+	discoveryURL := strings.TrimRight(ac.config.IssuerURL, "/") + openIDConfigurationPath
+
+	req, err := http.NewRequestWithContext(ctx, "GET", discoveryURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery request: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	tflog.Debug(ctx, "Discovering OpenID configuration", map[string]interface{}{
+		"url": discoveryURL,
+	})
+
+	resp, err := ac.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute discovery request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("OpenID discovery at %s failed with status: %d, body: %s", discoveryURL, resp.StatusCode, string(body))
+	}
+
+	var discovery OpenIDConfiguration
+	if err := json.NewDecoder(resp.Body).Decode(&discovery); err != nil {
+		return nil, fmt.Errorf("failed to decode OpenID configuration: %v", err)
+	}
+
+	// OIDC Discovery 4.3: the document must be issued by the configured
+	// issuer, otherwise credentials could be sent to another party's endpoint.
+	// Auth0 and others differ on the trailing slash, so it is not compared.
+	if strings.TrimRight(discovery.Issuer, "/") != strings.TrimRight(ac.config.IssuerURL, "/") {
+		return nil, fmt.Errorf("OpenID configuration at %s is for issuer %q, expected %q", discoveryURL, discovery.Issuer, ac.config.IssuerURL)
+	}
+
+	if discovery.TokenEndpoint == "" {
+		return nil, fmt.Errorf("OpenID configuration at %s does not advertise a token_endpoint", discoveryURL)
+	}
+
+	tflog.Debug(ctx, "Discovered token endpoint", map[string]interface{}{
+		"issuer":         discovery.Issuer,
+		"token_endpoint": discovery.TokenEndpoint,
+	})
+
+	return &discovery, nil
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// testIssuerServer serves an OpenID discovery document advertising issuer
+// (the server URL when empty) and a token endpoint on the same server.
+func testIssuerServer(t *testing.T, issuer string) (*httptest.Server, *int32, *int32) {
+	t.Helper()
+
+	var discoveryCalls, tokenCalls int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case openIDConfigurationPath:
+			atomic.AddInt32(&discoveryCalls, 1)
+			advertised := issuer
+			if advertised == "" {
+				advertised = server.URL + "/"
+			}
+			json.NewEncoder(w).Encode(OpenIDConfiguration{
+				Issuer:        advertised,
+				TokenEndpoint: server.URL + "/oauth/token",
+			})
+		case "/oauth/token":
+			atomic.AddInt32(&tokenCalls, 1)
+			if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "client_credentials" {
+				http.Error(w, `{"error": "invalid_request"}`, http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"access_token": "discovered", "expires_in": 3600}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &discoveryCalls, &tokenCalls
+}
+
+func TestGetTokenWithDiscovery(t *testing.T) {
+	server, discoveryCalls, tokenCalls := testIssuerServer(t, "")
+	ac := NewAuthClient(&AuthConfig{
+		AuthMethod:   AuthMethodClientCredentials,
+		ClientID:     "id",
+		ClientSecret: "secret",
+		IssuerURL:    server.URL,
+	})
+
+	for i := 0; i < 2; i++ {
+		ac.InvalidateToken("discovered")
+		token, err := ac.GetToken(context.Background())
+		if err != nil {
+			t.Fatalf("GetToken: %v", err)
+		}
+		if token != "discovered" {
+			t.Errorf("token = %q, want discovered", token)
+		}
+	}
+
+	if got := atomic.LoadInt32(discoveryCalls); got != 1 {
+		t.Errorf("discovery calls = %d, want 1", got)
+	}
+	if got := atomic.LoadInt32(tokenCalls); got != 2 {
+		t.Errorf("token calls = %d, want 2", got)
+	}
+}
+
+func TestGetTokenRejectsIssuerMismatch(t *testing.T) {
+	server, _, tokenCalls := testIssuerServer(t, "https://attacker.example.com")
+	ac := NewAuthClient(&AuthConfig{
+		AuthMethod:   AuthMethodClientCredentials,
+		ClientID:     "id",
+		ClientSecret: "secret",
+		IssuerURL:    server.URL,
+	})
+
+	_, err := ac.GetToken(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "attacker.example.com") {
+		t.Fatalf("expected an issuer mismatch error, got %v", err)
+	}
+	if got := atomic.LoadInt32(tokenCalls); got != 0 {
+		t.Errorf("token calls = %d, want 0: credentials were sent", got)
+	}
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryConfig controls how MakeRequest retries transient failures.
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	// RetryNonIdempotent allows retrying methods such as POST and PATCH,
+	// which may apply a change twice if the first attempt reached the server.
+	RetryNonIdempotent   bool
+	RetryableStatusCodes []int
+}
+
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:          3,
+		MinBackoff:           1 * time.Second,
+		MaxBackoff:           30 * time.Second,
+		RetryableStatusCodes: []int{429, 502, 503, 504},
+	}
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+// shouldRetry reports whether an attempt that produced resp or err should be retried.
+func (r RetryConfig) shouldRetry(method string, attempt int, resp *http.Response, err error) bool {
+	if attempt >= r.MaxAttempts {
+		return false
+	}
+
+	if !r.RetryNonIdempotent && !isIdempotentMethod(method) {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	for _, code := range r.RetryableStatusCodes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns how long to wait before the next attempt. A Retry-After
+// header takes precedence, otherwise the delay grows exponentially with jitter.
+func (r RetryConfig) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if r.MaxBackoff > 0 && wait > r.MaxBackoff {
+				return r.MaxBackoff
+			}
+			return wait
+		}
+	}
+
+	if r.MinBackoff <= 0 {
+		return 0
+	}
+
+	// Shifting can overflow for large attempt counts, which the MaxBackoff
+	// check below also catches.
+	wait := r.MinBackoff << (attempt - 1)
+	if wait <= 0 || (r.MaxBackoff > 0 && wait > r.MaxBackoff) {
+		wait = r.MaxBackoff
+	}
+
+	if wait <= 0 {
+		return 0
+	}
+
+	// Equal jitter keeps at least half the delay while spreading out
+	// concurrent clients that failed at the same time.
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// maxRetryAfterSeconds bounds a Retry-After value; backoff caps it further at MaxBackoff.
+const maxRetryAfterSeconds = int64(24 * time.Hour / time.Second)
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds >= 0 {
+		// Clamp before converting so a huge value cannot overflow to a
+		// negative duration that slips past MaxBackoff.
+		if seconds > maxRetryAfterSeconds {
+			seconds = maxRetryAfterSeconds
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+func sleepWithContext(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
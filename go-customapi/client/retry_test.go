@@ -0,0 +1,127 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	cases := []struct {
+		name  string
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{name: "empty", value: "", ok: false},
+		{name: "seconds", value: "5", want: 5 * time.Second, ok: true},
+		{name: "zero seconds", value: "0", want: 0, ok: true},
+		{name: "negative seconds", value: "-1", ok: false},
+		{name: "huge seconds clamped", value: "99999999999", want: 24 * time.Hour, ok: true},
+		{name: "beyond int64", value: "99999999999999999999", ok: false},
+		{name: "garbage", value: "soon", ok: false},
+		{name: "past date", value: "Mon, 02 Jan 2006 15:04:05 GMT", want: 0, ok: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tc.value)
+			if ok != tc.ok {
+				t.Fatalf("ok = %v, want %v", ok, tc.ok)
+			}
+			if got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("future date", func(t *testing.T) {
+		value := time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)
+		got, ok := parseRetryAfter(value)
+		if !ok || got <= 8*time.Second || got > 10*time.Second {
+			t.Errorf("got %v, %v; want about 10s", got, ok)
+		}
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	config := RetryConfig{MinBackoff: time.Second, MaxBackoff: 8 * time.Second}
+
+	retryAfter := func(value string) *http.Response {
+		return &http.Response{Header: http.Header{"Retry-After": []string{value}}}
+	}
+
+	cases := []struct {
+		name    string
+		config  RetryConfig
+		attempt int
+		resp    *http.Response
+		min     time.Duration
+		max     time.Duration
+	}{
+		{name: "first attempt", config: config, attempt: 1, min: 500 * time.Millisecond, max: time.Second},
+		{name: "grows exponentially", config: config, attempt: 3, min: 2 * time.Second, max: 4 * time.Second},
+		{name: "capped at max", config: config, attempt: 10, min: 4 * time.Second, max: 8 * time.Second},
+		{name: "shift overflow capped at max", config: config, attempt: 100, min: 4 * time.Second, max: 8 * time.Second},
+		{name: "retry-after wins", config: config, attempt: 1, resp: retryAfter("3"), min: 3 * time.Second, max: 3 * time.Second},
+		{name: "retry-after capped at max", config: config, attempt: 1, resp: retryAfter("60"), min: 8 * time.Second, max: 8 * time.Second},
+		{name: "huge retry-after capped at max", config: config, attempt: 1, resp: retryAfter("99999999999"), min: 8 * time.Second, max: 8 * time.Second},
+		{name: "invalid retry-after falls back", config: config, attempt: 1, resp: retryAfter("soon"), min: 500 * time.Millisecond, max: time.Second},
+		{name: "no min backoff", config: RetryConfig{MaxBackoff: time.Second}, attempt: 2, min: 0, max: 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				got := tc.config.backoff(tc.attempt, tc.resp)
+				if got < tc.min || got > tc.max {
+					t.Fatalf("backoff = %v, want between %v and %v", got, tc.min, tc.max)
+				}
+			}
+		})
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	config := DefaultRetryConfig()
+	status := func(code int) *http.Response {
+		return &http.Response{StatusCode: code}
+	}
+
+	cases := []struct {
+		name    string
+		config  RetryConfig
+		method  string
+		attempt int
+		resp    *http.Response
+		err     error
+		want    bool
+	}{
+		{name: "retryable status", config: config, method: http.MethodGet, attempt: 1, resp: status(503), want: true},
+		{name: "rate limited", config: config, method: http.MethodDelete, attempt: 2, resp: status(429), want: true},
+		{name: "non-retryable status", config: config, method: http.MethodGet, attempt: 1, resp: status(500), want: false},
+		{name: "success", config: config, method: http.MethodGet, attempt: 1, resp: status(200), want: false},
+		{name: "transport error", config: config, method: http.MethodPut, attempt: 1, err: errors.New("connection reset"), want: true},
+		{name: "attempts exhausted", config: config, method: http.MethodGet, attempt: 3, resp: status(503), want: false},
+		{name: "post not retried", config: config, method: http.MethodPost, attempt: 1, resp: status(503), want: false},
+		{name: "patch error not retried", config: config, method: http.MethodPatch, attempt: 1, err: errors.New("timeout"), want: false},
+		{
+			name:    "post retried when allowed",
+			config:  RetryConfig{MaxAttempts: 3, RetryNonIdempotent: true, RetryableStatusCodes: []int{503}},
+			method:  http.MethodPost,
+			attempt: 1,
+			resp:    status(503),
+			want:    true,
+		},
+		{name: "retries disabled", config: RetryConfig{MaxAttempts: 1}, method: http.MethodGet, attempt: 1, err: errors.New("timeout"), want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.config.shouldRetry(tc.method, tc.attempt, tc.resp, tc.err); got != tc.want {
+				t.Errorf("shouldRetry = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -55,4 +55,19 @@ func (r *APIResponse) UnmarshalJSON(data []byte) error {
 		Api:            tmp.Api,
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// ParseAPIResponse decodes a response body through the APIResponse envelope.
+// It reports false when the body is not a JSON object.
+func ParseAPIResponse(body []byte) (*APIResponse, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, false
+	}
+
+	var envelope APIResponse
+	if err := envelope.UnmarshalJSON(body); err != nil {
+		return nil, false
+	}
+	return &envelope, true
+}
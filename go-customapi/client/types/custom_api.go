@@ -11,6 +11,8 @@ type CustomAPIRequest struct {
 	Headers     map[string]string `json:"headers,omitempty"`
 	Body        json.RawMessage   `json:"body,omitempty"`
 	QueryParams map[string]string `json:"query_params,omitempty"`
+	// ExpectedStatusCodes overrides which status codes count as success.
+	ExpectedStatusCodes []int `json:"expected_status_codes,omitempty"`
 }
 
 type CustomAPIResponse struct {
@@ -19,6 +21,10 @@ type CustomAPIResponse struct {
 	Body       json.RawMessage   `json:"body"`
 	Success    bool              `json:"success"`
 	Error      string            `json:"error,omitempty"`
+	// Envelope is the decoded APIResponse envelope, nil when the body is not a JSON object.
+	Envelope *APIResponse `json:"-"`
+	// APIError describes the failure when Success is false.
+	APIError *APIError `json:"-"`
 }
 
 type UserProfile struct {
@@ -57,11 +63,41 @@ type Organization struct {
 }
 
 type APIError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
+	Code           int      `json:"code"`
+	Message        string   `json:"message"`
+	Details        string   `json:"details,omitempty"`
+	Status         string   `json:"status,omitempty"`
+	StatusMessages []string `json:"status_messages,omitempty"`
+	RequestId      string   `json:"requestId,omitempty"`
+	OperationId    string   `json:"operationId,omitempty"`
+}
+
+// NewAPIError builds an APIError for a failed response, taking the message and
+// correlation IDs from the envelope when one is present.
+func NewAPIError(statusCode int, envelope *APIResponse) *APIError {
+	apiErr := &APIError{
+		Code:    statusCode,
+		Message: fmt.Sprintf("Request failed with status %d", statusCode),
+	}
+
+	if envelope == nil {
+		return apiErr
+	}
+
+	if envelope.Message != "" {
+		apiErr.Message = envelope.Message
+	}
+	apiErr.Status = envelope.Status
+	apiErr.StatusMessages = envelope.StatusMessages
+	apiErr.RequestId = envelope.RequestId
+	apiErr.OperationId = envelope.OperationId
+
+	return apiErr
 }
 
 func (e *APIError) Error() string {
+	if e.RequestId != "" {
+		return fmt.Sprintf("API Error %d: %s (request ID: %s)", e.Code, e.Message, e.RequestId)
+	}
 	return fmt.Sprintf("API Error %d: %s", e.Code, e.Message)
 }
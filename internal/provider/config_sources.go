@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-customapi/go-customapi/client"
+)
+
+// configResolver resolves provider settings in precedence order: the provider
+// block, then environment variables, then the optional env_file. It records
+// the source of every value so the chain can be followed in debug logs.
+type configResolver struct {
+	env     *client.Config
+	file    *client.Config
+	envFile string
+	sources map[string]interface{}
+}
+
+// configCandidate is a value set in the provider block, labelled with where.
+type configCandidate struct {
+	source string
+	value  string
+}
+
+func newConfigResolver(env *client.Config, file *client.Config, envFile string) *configResolver {
+	if file == nil {
+		file = &client.Config{}
+	}
+
+	return &configResolver{
+		env:     env,
+		file:    file,
+		envFile: envFile,
+		sources: make(map[string]interface{}),
+	}
+}
+
+func providerValue(value types.String) configCandidate {
+	return configCandidate{source: "provider block", value: value.ValueString()}
+}
+
+func profileValue(name string, value types.String) configCandidate {
+	return configCandidate{source: fmt.Sprintf("environments[%q]", name), value: value.ValueString()}
+}
+
+// resolve returns the first non-empty value among the configured candidates,
+// the environment variable and the env_file entry named variable.
+func (r *configResolver) resolve(name string, variable string, field func(*client.Config) string, configured ...configCandidate) string {
+	for _, candidate := range configured {
+		if candidate.value != "" {
+			r.sources[name] = candidate.source
+			return candidate.value
+		}
+	}
+
+	if variable != "" {
+		if value := field(r.env); value != "" {
+			r.sources[name] = "environment variable " + variable
+			return value
+		}
+
+		if value := field(r.file); value != "" {
+			r.sources[name] = fmt.Sprintf("%s in %s", variable, r.envFile)
+			return value
+		}
+	}
+
+	r.sources[name] = "unset"
+	return ""
+}
+
+// environmentVariable returns variable when values may be taken from the
+// environment for this setting, and "" otherwise.
+func environmentVariable(enabled bool, variable string) string {
+	if !enabled {
+		return ""
+	}
+	return variable
+}
@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-customapi/go-customapi/client"
+)
+
+func TestConfigResolverPrecedence(t *testing.T) {
+	username := func(c *client.Config) string { return c.Username }
+
+	cases := []struct {
+		name       string
+		provider   types.String
+		profile    types.String
+		env        string
+		file       string
+		variable   string
+		want       string
+		wantSource string
+	}{
+		{
+			name:       "provider block wins",
+			provider:   types.StringValue("block"),
+			profile:    types.StringValue("profile"),
+			env:        "env",
+			file:       "file",
+			want:       "block",
+			wantSource: "provider block",
+		},
+		{
+			name:       "profile before environment",
+			provider:   types.StringNull(),
+			profile:    types.StringValue("profile"),
+			env:        "env",
+			file:       "file",
+			want:       "profile",
+			wantSource: `environments["dev"]`,
+		},
+		{
+			name:       "environment before env_file",
+			provider:   types.StringNull(),
+			profile:    types.StringNull(),
+			env:        "env",
+			file:       "file",
+			want:       "env",
+			wantSource: "environment variable CUSTOMAPI_USERNAME",
+		},
+		{
+			name:       "env_file last",
+			provider:   types.StringNull(),
+			profile:    types.StringNull(),
+			file:       "file",
+			want:       "file",
+			wantSource: "CUSTOMAPI_USERNAME in test.env",
+		},
+		{
+			name:       "empty provider value falls through",
+			provider:   types.StringValue(""),
+			profile:    types.StringNull(),
+			env:        "env",
+			want:       "env",
+			wantSource: "environment variable CUSTOMAPI_USERNAME",
+		},
+		{
+			name:       "environment disabled",
+			provider:   types.StringNull(),
+			profile:    types.StringNull(),
+			env:        "env",
+			file:       "file",
+			variable:   "-",
+			wantSource: "unset",
+		},
+		{
+			name:       "unset",
+			provider:   types.StringNull(),
+			profile:    types.StringNull(),
+			wantSource: "unset",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resolver := newConfigResolver(&client.Config{Username: tc.env}, &client.Config{Username: tc.file}, "test.env")
+
+			variable := environmentVariable(tc.variable != "-", "CUSTOMAPI_USERNAME")
+			got := resolver.resolve("username", variable, username, providerValue(tc.provider), profileValue("dev", tc.profile))
+			if got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+			if source := resolver.sources["username"]; source != tc.wantSource {
+				t.Errorf("source = %q, want %q", source, tc.wantSource)
+			}
+		})
+	}
+}
+
+func TestConfigResolverWithoutEnvFile(t *testing.T) {
+	resolver := newConfigResolver(&client.Config{}, nil, "")
+
+	got := resolver.resolve("username", "CUSTOMAPI_USERNAME", func(c *client.Config) string { return c.Username })
+	if got != "" || resolver.sources["username"] != "unset" {
+		t.Errorf("got %q from %v, want unset", got, resolver.sources["username"])
+	}
+}
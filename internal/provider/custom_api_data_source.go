@@ -5,24 +5,35 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"terraform-provider-customapi/go-customapi/client"
 	clienttypes "terraform-provider-customapi/go-customapi/client/types"
 )
 
-type CustomAPIDataSource struct{
+type CustomAPIDataSource struct {
 	client *client.CustomAPIClient
 }
 
-type CustomAPIDataSourceModel struct{
-	Endpoint   types.String `tfsdk:"endpoint"`
-	OrgID      types.String `tfsdk:"org_id"`
-	QueryParams map[string]types.String `tfsdk:"query_params"`
-	Response   types.String `tfsdk:"response"`
-	StatusCode types.Int64  `tfsdk:"status_code"`
-	Success    types.Bool   `tfsdk:"success"`
-	Error      types.String `tfsdk:"error"`
+type CustomAPIDataSourceModel struct {
+	Endpoint               types.String            `tfsdk:"endpoint"`
+	OrgID                  types.String            `tfsdk:"org_id"`
+	QueryParams            map[string]types.String `tfsdk:"query_params"`
+	ExpectedStatusCodes    []types.Int64           `tfsdk:"expected_status_codes"`
+	FailOnError            types.Bool              `tfsdk:"fail_on_error"`
+	SuppressStatusMessages []types.String          `tfsdk:"suppress_status_messages"`
+	UnwrapEnvelope         types.Bool              `tfsdk:"unwrap_envelope"`
+	Data                   types.String            `tfsdk:"data"`
+	RequestID              types.String            `tfsdk:"request_id"`
+	OperationID            types.String            `tfsdk:"operation_id"`
+	APIStatus              types.String            `tfsdk:"api_status"`
+	Message                types.String            `tfsdk:"message"`
+	Response               types.String            `tfsdk:"response"`
+	ResponseJSON           types.Dynamic           `tfsdk:"response_json"`
+	StatusCode             types.Int64             `tfsdk:"status_code"`
+	Success                types.Bool              `tfsdk:"success"`
+	Error                  types.String            `tfsdk:"error"`
 }
 
 func NewCustomAPIDataSource() datasource.DataSource {
@@ -56,20 +67,71 @@ func (d *CustomAPIDataSource) Schema(ctx context.Context, req datasource.SchemaR
 			"endpoint": schema.StringAttribute{
 				Required:    true,
 				Description: "API endpoint to call",
+				Validators: []validator.String{
+					pathOrURLValidator{},
+				},
 			},
 			"org_id": schema.StringAttribute{
 				Optional:    true,
-				Description: "Organization ID",
+				Description: "Organization ID sent in the current-organization header, overriding the provider org_id; set to \"\" to send no header",
 			},
 			"query_params": schema.MapAttribute{
 				ElementType: types.StringType,
 				Optional:    true,
 				Description: "Query parameters",
 			},
+			"expected_status_codes": schema.ListAttribute{
+				ElementType: types.Int64Type,
+				Optional:    true,
+				Description: "Status codes treated as success (defaults to the provider expected_status_codes, then any 2xx)",
+				Validators: []validator.List{
+					statusCodesValidator(),
+				},
+			},
+			"fail_on_error": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether an unexpected status code fails the read. When false the failure is only recorded in error (defaults to true)",
+			},
+			"suppress_status_messages": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Regular expressions for envelope status_messages that should not be reported as warnings",
+				Validators: []validator.List{
+					regularExpressionsValidator(),
+				},
+			},
+			"unwrap_envelope": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Decode the response through the API envelope and expose its fields as data, request_id, operation_id, api_status and message",
+			},
+			"data": schema.StringAttribute{
+				Computed:    true,
+				Description: "JSON encoded data field of the response envelope, set when unwrap_envelope is true",
+			},
+			"request_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "requestId of the response envelope, set when unwrap_envelope is true",
+			},
+			"operation_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "operationId of the response envelope, set when unwrap_envelope is true",
+			},
+			"api_status": schema.StringAttribute{
+				Computed:    true,
+				Description: "status of the response envelope, set when unwrap_envelope is true",
+			},
+			"message": schema.StringAttribute{
+				Computed:    true,
+				Description: "message of the response envelope, set when unwrap_envelope is true",
+			},
 			"response": schema.StringAttribute{
 				Computed:    true,
 				Description: "API response body",
 			},
+			"response_json": schema.DynamicAttribute{
+				Computed:    true,
+				Description: "API response body decoded from JSON, null when the body is not JSON",
+			},
 			"status_code": schema.Int64Attribute{
 				Computed:    true,
 				Description: "HTTP status code",
@@ -103,16 +165,24 @@ func (d *CustomAPIDataSource) Read(ctx context.Context, req datasource.ReadReque
 	}
 
 	apiReq := &clienttypes.CustomAPIRequest{
-		Method:      "GET",
-		URL:         data.Endpoint.ValueString(),
-		QueryParams: queryParams,
+		Method:              "GET",
+		URL:                 data.Endpoint.ValueString(),
+		QueryParams:         queryParams,
+		ExpectedStatusCodes: int64sToInts(data.ExpectedStatusCodes),
 		Headers: map[string]string{
 			"Accept": "application/json",
 		},
 	}
 
+	// A configured org_id overrides the provider default; an empty one sends
+	// no organization header.
 	if !data.OrgID.IsNull() {
-		apiReq.Headers["current-organization"] = data.OrgID.ValueString()
+		apiReq.Headers[client.OrganizationHeader] = data.OrgID.ValueString()
+	}
+
+	statusFilters, ok := compileStatusMessageFilters(&resp.Diagnostics, data.SuppressStatusMessages)
+	if !ok {
+		return
 	}
 
 	apiResp, err := apiClient.MakeRequest(ctx, apiReq)
@@ -124,14 +194,31 @@ func (d *CustomAPIDataSource) Read(ctx context.Context, req datasource.ReadReque
 		return
 	}
 
+	if !apiResp.Success && failOnError(data.FailOnError) {
+		addResponseError(&resp.Diagnostics, "Data Source Request Failed", apiReq, apiResp)
+		return
+	}
+
+	addStatusMessageWarnings(&resp.Diagnostics, apiReq, apiResp, statusFilters)
+
 	data.Response = types.StringValue(string(apiResp.Body))
+	data.ResponseJSON = jsonToDynamic(apiResp.Body)
 	data.StatusCode = types.Int64Value(int64(apiResp.StatusCode))
 	data.Success = types.BoolValue(apiResp.Success)
-	
+
 	if !apiResp.Success {
 		data.Error = types.StringValue(apiResp.Error)
+	} else {
+		data.Error = types.StringNull()
 	}
 
+	envelope := unwrapEnvelope(data.UnwrapEnvelope, apiResp)
+	data.Data = envelope.Data
+	data.RequestID = envelope.RequestID
+	data.OperationID = envelope.OperationID
+	data.APIStatus = envelope.APIStatus
+	data.Message = envelope.Message
+
 	tflog.Debug(ctx, "Data source read completed", map[string]interface{}{
 		"endpoint":    data.Endpoint.ValueString(),
 		"status_code": apiResp.StatusCode,
@@ -3,30 +3,66 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/url"
+	urlpath "path"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"terraform-provider-customapi/go-customapi/client"
 	clienttypes "terraform-provider-customapi/go-customapi/client/types"
 )
 
-type CustomAPIResource struct{
+type CustomAPIResource struct {
 	client *client.CustomAPIClient
 }
 
-type CustomAPIResourceModel struct{
-	ID          types.String `tfsdk:"id"`
-	Endpoint    types.String `tfsdk:"endpoint"`
-	Method      types.String `tfsdk:"method"`
-	Body        types.String `tfsdk:"body"`
-	OrgID       types.String `tfsdk:"org_id"`
-	Headers     map[string]types.String `tfsdk:"headers"`
-	QueryParams map[string]types.String `tfsdk:"query_params"`
-	Response    types.String `tfsdk:"response"`
-	StatusCode  types.Int64  `tfsdk:"status_code"`
-	Success     types.Bool   `tfsdk:"success"`
-	Error       types.String `tfsdk:"error"`
+type CustomAPIResourceModel struct {
+	ID                     types.String            `tfsdk:"id"`
+	Endpoint               types.String            `tfsdk:"endpoint"`
+	Method                 types.String            `tfsdk:"method"`
+	CreateMethod           types.String            `tfsdk:"create_method"`
+	CreatePath             types.String            `tfsdk:"create_path"`
+	ReadMethod             types.String            `tfsdk:"read_method"`
+	ReadPath               types.String            `tfsdk:"read_path"`
+	UpdateMethod           types.String            `tfsdk:"update_method"`
+	UpdatePath             types.String            `tfsdk:"update_path"`
+	UpdateStrategy         types.String            `tfsdk:"update_strategy"`
+	DestroyMethod          types.String            `tfsdk:"destroy_method"`
+	DestroyPath            types.String            `tfsdk:"destroy_path"`
+	IDAttribute            types.String            `tfsdk:"id_attribute"`
+	GoneStatusCodes        []types.Int64           `tfsdk:"gone_status_codes"`
+	ExpectedStatusCodes    []types.Int64           `tfsdk:"expected_status_codes"`
+	FailOnError            types.Bool              `tfsdk:"fail_on_error"`
+	SuppressStatusMessages []types.String          `tfsdk:"suppress_status_messages"`
+	Body                   NormalizedJSONValue     `tfsdk:"body"`
+	BodyJSON               types.Dynamic           `tfsdk:"body_json"`
+	DetectDrift            types.Bool              `tfsdk:"detect_drift"`
+	IgnoreFields           []types.String          `tfsdk:"ignore_fields"`
+	ReplaceOnChange        []types.String          `tfsdk:"replace_on_change"`
+	OrgID                  types.String            `tfsdk:"org_id"`
+	Headers                map[string]types.String `tfsdk:"headers"`
+	QueryParams            map[string]types.String `tfsdk:"query_params"`
+	UnwrapEnvelope         types.Bool              `tfsdk:"unwrap_envelope"`
+	Data                   types.String            `tfsdk:"data"`
+	RequestID              types.String            `tfsdk:"request_id"`
+	OperationID            types.String            `tfsdk:"operation_id"`
+	APIStatus              types.String            `tfsdk:"api_status"`
+	Message                types.String            `tfsdk:"message"`
+	Response               NormalizedJSONValue     `tfsdk:"response"`
+	ResponseJSON           types.Dynamic           `tfsdk:"response_json"`
+	StatusCode             types.Int64             `tfsdk:"status_code"`
+	Success                types.Bool              `tfsdk:"success"`
+	Error                  types.String            `tfsdk:"error"`
 }
 
 func NewCustomAPIResource() resource.Resource {
@@ -60,22 +96,165 @@ func (r *CustomAPIResource) Schema(ctx context.Context, req resource.SchemaReque
 			"id": schema.StringAttribute{
 				Computed:    true,
 				Description: "Resource identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"endpoint": schema.StringAttribute{
 				Required:    true,
 				Description: "API endpoint",
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceIfPreviouslySet(),
+				},
+				Validators: []validator.String{
+					pathOrURLValidator{},
+				},
 			},
 			"method": schema.StringAttribute{
-				Required:    true,
-				Description: "HTTP method (GET, POST, PUT, DELETE)",
+				Optional:    true,
+				Description: "HTTP method used to create the object when create_method is not set (defaults to POST)",
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceIfPreviouslySet(),
+				},
+				Validators: []validator.String{
+					httpMethodValidator(),
+				},
+			},
+			"create_method": schema.StringAttribute{
+				Optional:    true,
+				Description: "HTTP method used to create the object (defaults to method, then POST)",
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceIfPreviouslySet(),
+				},
+				Validators: []validator.String{
+					httpMethodValidator(),
+				},
+			},
+			"create_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path used to create the object (defaults to endpoint)",
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceIfPreviouslySet(),
+				},
+				Validators: []validator.String{
+					pathOrURLValidator{},
+				},
+			},
+			"read_method": schema.StringAttribute{
+				Optional:    true,
+				Description: "HTTP method used to read the object (defaults to GET)",
+				Validators: []validator.String{
+					httpMethodValidator(),
+				},
+			},
+			"read_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path used to read the object, {id} is replaced with the resource ID. Refresh is skipped when unset",
+				Validators: []validator.String{
+					pathOrURLValidator{},
+				},
+			},
+			"update_method": schema.StringAttribute{
+				Optional:    true,
+				Description: "HTTP method used to update the object (defaults to PUT, or PATCH when update_strategy is a patch)",
+				Validators: []validator.String{
+					httpMethodValidator(),
+				},
+			},
+			"update_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path used to update the object, {id} is replaced with the resource ID (defaults to read_path)",
+				Validators: []validator.String{
+					pathOrURLValidator{},
+				},
+			},
+			"update_strategy": schema.StringAttribute{
+				Optional:    true,
+				Description: "How updates are sent: put replays the full body, merge_patch sends an RFC 7396 JSON Merge Patch and json_patch sends an RFC 6902 JSON Patch computed from the prior and planned body (defaults to put)",
+				Validators: []validator.String{
+					stringvalidator.OneOf(updateStrategyPut, updateStrategyMergePatch, updateStrategyJSONPatch),
+				},
+			},
+			"destroy_method": schema.StringAttribute{
+				Optional:    true,
+				Description: "HTTP method used to delete the object (defaults to DELETE)",
+				Validators: []validator.String{
+					httpMethodValidator(),
+				},
+			},
+			"destroy_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path used to delete the object, {id} is replaced with the resource ID (defaults to read_path). Destroy only removes the object from state when unset",
+				Validators: []validator.String{
+					pathOrURLValidator{},
+				},
+			},
+			"id_attribute": schema.StringAttribute{
+				Optional:    true,
+				Description: "JSON pointer (/data/id) or dotted path (data.id) to the object ID in the create response, also looked up inside the data envelope field (defaults to id). Falls back to the last segment of the Location header",
+			},
+			"gone_status_codes": schema.ListAttribute{
+				ElementType: types.Int64Type,
+				Optional:    true,
+				Description: "Status codes returned by read that mean the object no longer exists and should be removed from state (defaults to 404 and 410)",
+				Validators: []validator.List{
+					statusCodesValidator(),
+				},
+			},
+			"expected_status_codes": schema.ListAttribute{
+				ElementType: types.Int64Type,
+				Optional:    true,
+				Description: "Status codes treated as success (defaults to the provider expected_status_codes, then any 2xx)",
+				Validators: []validator.List{
+					statusCodesValidator(),
+				},
+			},
+			"fail_on_error": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether an unexpected status code fails the operation. When false the failure is only recorded in error (defaults to true)",
+			},
+			"suppress_status_messages": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Regular expressions for envelope status_messages that should not be reported as warnings",
+				Validators: []validator.List{
+					regularExpressionsValidator(),
+				},
 			},
 			"body": schema.StringAttribute{
+				CustomType:  NormalizedJSONType{},
+				Optional:    true,
+				Description: "Request body. Values written back by the provider are compared as JSON, ignoring key order and whitespace",
+				Validators: []validator.String{
+					jsonStringValidator{},
+				},
+			},
+			"body_json": schema.DynamicAttribute{
 				Optional:    true,
-				Description: "Request body",
+				Description: "Request body as a Terraform value, sent JSON encoded. Use instead of body",
+			},
+			"detect_drift": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Compare the keys of body or body_json with the object returned by read and plan an update when they differ",
+			},
+			"ignore_fields": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Dotted paths of server-managed body fields, such as updatedAt, that are ignored by detect_drift",
+			},
+			"replace_on_change": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Dotted paths of body keys whose change forces the object to be replaced instead of updated",
 			},
 			"org_id": schema.StringAttribute{
 				Optional:    true,
-				Description: "Organization ID",
+				Computed:    true,
+				Description: "Organization ID sent in the current-organization header, overriding the provider org_id; set to \"\" to send no header. Defaults to the provider org_id, which is recorded in state",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					requiresReplaceIfPreviouslySet(),
+				},
 			},
 			"headers": schema.MapAttribute{
 				ElementType: types.StringType,
@@ -87,10 +266,39 @@ func (r *CustomAPIResource) Schema(ctx context.Context, req resource.SchemaReque
 				Optional:    true,
 				Description: "Query parameters",
 			},
+			"unwrap_envelope": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Decode the response through the API envelope and expose its fields as data, request_id, operation_id, api_status and message",
+			},
+			"data": schema.StringAttribute{
+				Computed:    true,
+				Description: "JSON encoded data field of the response envelope, set when unwrap_envelope is true",
+			},
+			"request_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "requestId of the response envelope, set when unwrap_envelope is true",
+			},
+			"operation_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "operationId of the response envelope, set when unwrap_envelope is true",
+			},
+			"api_status": schema.StringAttribute{
+				Computed:    true,
+				Description: "status of the response envelope, set when unwrap_envelope is true",
+			},
+			"message": schema.StringAttribute{
+				Computed:    true,
+				Description: "message of the response envelope, set when unwrap_envelope is true",
+			},
 			"response": schema.StringAttribute{
+				CustomType:  NormalizedJSONType{},
 				Computed:    true,
 				Description: "API response body",
 			},
+			"response_json": schema.DynamicAttribute{
+				Computed:    true,
+				Description: "API response body decoded from JSON, null when the body is not JSON",
+			},
 			"status_code": schema.Int64Attribute{
 				Computed:    true,
 				Description: "HTTP status code",
@@ -107,6 +315,137 @@ func (r *CustomAPIResource) Schema(ctx context.Context, req resource.SchemaReque
 	}
 }
 
+func (r *CustomAPIResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.Conflicting(
+			path.MatchRoot("body"),
+			path.MatchRoot("body_json"),
+		),
+		patchStrategyPathValidator{},
+	}
+}
+
+// ModifyPlan plans the provider default org_id and forces replacement when
+// it or a body key listed in replace_on_change changes.
+func (r *CustomAPIResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	r.planDefaultOrgID(ctx, req, resp)
+
+	if req.State.Raw.IsNull() || resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only the attributes used here are read: decoding the whole model would
+	// fail whenever an unrelated collection such as headers is unknown.
+	var replaceOnChangeList types.List
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("replace_on_change"), &replaceOnChangeList)...)
+
+	if resp.Diagnostics.HasError() || replaceOnChangeList.IsNull() || replaceOnChangeList.IsUnknown() {
+		return
+	}
+
+	var replaceOnChangeValues []types.String
+	resp.Diagnostics.Append(replaceOnChangeList.ElementsAs(ctx, &replaceOnChangeValues, false)...)
+
+	plan := bodyAttributes(ctx, resp.Plan, &resp.Diagnostics)
+	state := bodyAttributes(ctx, req.State, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Body.IsUnknown() || plan.BodyJSON.IsUnknown() || plan.BodyJSON.IsUnderlyingValueUnknown() {
+		return
+	}
+
+	priorBody, err := requestBody(state)
+	if err != nil {
+		return
+	}
+
+	plannedBody, err := requestBody(plan)
+	if err != nil {
+		return
+	}
+
+	replaceOnChange := make([]string, 0, len(replaceOnChangeValues))
+	for _, value := range replaceOnChangeValues {
+		if value.IsNull() || value.IsUnknown() {
+			continue
+		}
+		replaceOnChange = append(replaceOnChange, value.ValueString())
+	}
+
+	changed := changedBodyPaths(priorBody, plannedBody, replaceOnChange)
+	if len(changed) == 0 {
+		return
+	}
+
+	tflog.Debug(ctx, "Body keys listed in replace_on_change changed", map[string]interface{}{
+		"keys": changed,
+	})
+
+	if !plan.BodyJSON.IsNull() {
+		resp.RequiresReplace.Append(path.Root("body_json"))
+	} else {
+		resp.RequiresReplace.Append(path.Root("body"))
+	}
+}
+
+// attributeGetter is implemented by tfsdk.Plan and tfsdk.State.
+type attributeGetter interface {
+	GetAttribute(ctx context.Context, path path.Path, target interface{}) diag.Diagnostics
+}
+
+// bodyAttributes reads only body and body_json into a model, for use with requestBody.
+func bodyAttributes(ctx context.Context, source attributeGetter, diags *diag.Diagnostics) CustomAPIResourceModel {
+	var data CustomAPIResourceModel
+	diags.Append(source.GetAttribute(ctx, path.Root("body"), &data.Body)...)
+	diags.Append(source.GetAttribute(ctx, path.Root("body_json"), &data.BodyJSON)...)
+	return data
+}
+
+// planDefaultOrgID plans the provider org_id when the configuration leaves
+// org_id unset, so a change to the provider default shows in the plan. Like
+// a configured org_id, it replaces the object when it changes. Objects whose
+// state has no org_id, created before it was recorded or imported without
+// one, keep it unset and never get an org_id-only update.
+func (r *CustomAPIResource) planDefaultOrgID(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var configured types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("org_id"), &configured)...)
+	if resp.Diagnostics.HasError() || !configured.IsNull() {
+		return
+	}
+
+	var prior types.String
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("org_id"), &prior)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if prior.IsNull() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("org_id"), types.StringNull())...)
+			return
+		}
+	}
+
+	// The client is not configured yet when provider values are unknown.
+	if r.client == nil {
+		return
+	}
+
+	orgID := types.StringValue(r.client.DefaultOrgID())
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("org_id"), orgID)...)
+
+	if !req.State.Raw.IsNull() && !prior.Equal(orgID) {
+		resp.RequiresReplace.Append(path.Root("org_id"))
+	}
+}
+
 func (r *CustomAPIResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data CustomAPIResourceModel
 
@@ -116,9 +455,27 @@ func (r *CustomAPIResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
+	// Record the organization the object is created in.
+	if data.OrgID.IsUnknown() {
+		data.OrgID = types.StringValue(r.client.DefaultOrgID())
+	}
+
 	apiClient := r.client
 
-	apiReq := r.buildAPIRequest(data)
+	op := r.createOperation(data)
+	apiReq, err := r.buildAPIRequest(data, op, true)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Request Body",
+			fmt.Sprintf("Failed to encode body_json: %v", err),
+		)
+		return
+	}
+
+	statusFilters, ok := compileStatusMessageFilters(&resp.Diagnostics, data.SuppressStatusMessages)
+	if !ok {
+		return
+	}
 
 	apiResp, err := apiClient.MakeRequest(ctx, apiReq)
 	if err != nil {
@@ -129,12 +486,28 @@ func (r *CustomAPIResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
+	if !apiResp.Success && failOnError(data.FailOnError) {
+		addResponseError(&resp.Diagnostics, "Create Request Failed", apiReq, apiResp)
+		return
+	}
+
+	addStatusMessageWarnings(&resp.Diagnostics, apiReq, apiResp, statusFilters)
+
 	r.updateModelFromResponse(&data, apiResp)
-	data.ID = types.StringValue(fmt.Sprintf("%s-%s", data.Endpoint.ValueString(), data.Method.ValueString()))
+
+	id, ok := r.resolveID(data, apiResp)
+	if !ok {
+		id = fmt.Sprintf("%s-%s", data.Endpoint.ValueString(), op.method)
+		resp.Diagnostics.AddWarning(
+			"Resource ID Not Found",
+			fmt.Sprintf("Could not find %q in the create response or a Location header, using %q as the resource ID", stringOrDefault(data.IDAttribute, "id"), id),
+		)
+	}
+	data.ID = types.StringValue(id)
 
 	tflog.Debug(ctx, "Resource created", map[string]interface{}{
-		"endpoint":    data.Endpoint.ValueString(),
-		"method":      data.Method.ValueString(),
+		"path":        apiReq.URL,
+		"method":      apiReq.Method,
 		"status_code": apiResp.StatusCode,
 		"success":     apiResp.Success,
 	})
@@ -151,9 +524,29 @@ func (r *CustomAPIResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	op := r.readOperation(data)
+	if op.path == "" {
+		tflog.Debug(ctx, "No read_path configured, skipping refresh", map[string]interface{}{
+			"id": data.ID.ValueString(),
+		})
+		return
+	}
+
 	apiClient := r.client
 
-	apiReq := r.buildAPIRequest(data)
+	apiReq, err := r.buildAPIRequest(data, op, false)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Request Body",
+			fmt.Sprintf("Failed to encode body_json: %v", err),
+		)
+		return
+	}
+
+	statusFilters, ok := compileStatusMessageFilters(&resp.Diagnostics, data.SuppressStatusMessages)
+	if !ok {
+		return
+	}
 
 	apiResp, err := apiClient.MakeRequest(ctx, apiReq)
 	if err != nil {
@@ -164,11 +557,36 @@ func (r *CustomAPIResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	if isGoneStatus(data, apiResp.StatusCode) {
+		tflog.Warn(ctx, "Resource no longer exists, removing from state", map[string]interface{}{
+			"id":          data.ID.ValueString(),
+			"path":        apiReq.URL,
+			"status_code": apiResp.StatusCode,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if !apiResp.Success && failOnError(data.FailOnError) {
+		addResponseError(&resp.Diagnostics, "Read Request Failed", apiReq, apiResp)
+		return
+	}
+
+	addStatusMessageWarnings(&resp.Diagnostics, apiReq, apiResp, statusFilters)
+
 	r.updateModelFromResponse(&data, apiResp)
 
+	if err := r.reconcileDrift(ctx, &data, apiResp); err != nil {
+		resp.Diagnostics.AddError(
+			"Drift Detection Failed",
+			fmt.Sprintf("Failed to compare body_json with the remote object: %v", err),
+		)
+		return
+	}
+
 	tflog.Debug(ctx, "Resource read", map[string]interface{}{
-		"endpoint":    data.Endpoint.ValueString(),
-		"method":      data.Method.ValueString(),
+		"path":        apiReq.URL,
+		"method":      apiReq.Method,
 		"status_code": apiResp.StatusCode,
 		"success":     apiResp.Success,
 	})
@@ -178,16 +596,58 @@ func (r *CustomAPIResource) Read(ctx context.Context, req resource.ReadRequest,
 
 func (r *CustomAPIResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var data CustomAPIResourceModel
+	var state CustomAPIResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	data.ID = state.ID
+	if data.OrgID.IsUnknown() {
+		data.OrgID = state.OrgID
+	}
+
 	apiClient := r.client
 
-	apiReq := r.buildAPIRequest(data)
+	apiReq, err := r.buildAPIRequest(data, r.updateOperation(data), true)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Request Body",
+			fmt.Sprintf("Failed to encode body_json: %v", err),
+		)
+		return
+	}
+
+	if strategy := stringOrDefault(data.UpdateStrategy, updateStrategyPut); strategy != updateStrategyPut {
+		priorBody, err := requestBody(state)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Request Body",
+				fmt.Sprintf("Failed to encode prior body_json: %v", err),
+			)
+			return
+		}
+
+		patch, contentType, err := buildPatch(strategy, priorBody, apiReq.Body)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Failed to Build Patch",
+				fmt.Sprintf("Failed to compute %s update: %v", strategy, err),
+			)
+			return
+		}
+
+		apiReq.Body = patch
+		apiReq.Headers["Content-Type"] = contentType
+	}
+
+	statusFilters, ok := compileStatusMessageFilters(&resp.Diagnostics, data.SuppressStatusMessages)
+	if !ok {
+		return
+	}
 
 	apiResp, err := apiClient.MakeRequest(ctx, apiReq)
 	if err != nil {
@@ -198,11 +658,18 @@ func (r *CustomAPIResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
+	if !apiResp.Success && failOnError(data.FailOnError) {
+		addResponseError(&resp.Diagnostics, "Update Request Failed", apiReq, apiResp)
+		return
+	}
+
+	addStatusMessageWarnings(&resp.Diagnostics, apiReq, apiResp, statusFilters)
+
 	r.updateModelFromResponse(&data, apiResp)
 
 	tflog.Debug(ctx, "Resource updated", map[string]interface{}{
-		"endpoint":    data.Endpoint.ValueString(),
-		"method":      data.Method.ValueString(),
+		"path":        apiReq.URL,
+		"method":      apiReq.Method,
 		"status_code": apiResp.StatusCode,
 		"success":     apiResp.Success,
 	})
@@ -219,9 +686,29 @@ func (r *CustomAPIResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
+	op := r.destroyOperation(data)
+	if op.path == "" {
+		tflog.Debug(ctx, "No destroy_path or read_path configured, removing from state only", map[string]interface{}{
+			"id": data.ID.ValueString(),
+		})
+		return
+	}
+
 	apiClient := r.client
 
-	apiReq := r.buildAPIRequest(data)
+	apiReq, err := r.buildAPIRequest(data, op, false)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Request Body",
+			fmt.Sprintf("Failed to encode body_json: %v", err),
+		)
+		return
+	}
+
+	statusFilters, ok := compileStatusMessageFilters(&resp.Diagnostics, data.SuppressStatusMessages)
+	if !ok {
+		return
+	}
 
 	apiResp, err := apiClient.MakeRequest(ctx, apiReq)
 	if err != nil {
@@ -232,15 +719,123 @@ func (r *CustomAPIResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
+	if !apiResp.Success && !isGoneStatus(data, apiResp.StatusCode) && failOnError(data.FailOnError) {
+		addResponseError(&resp.Diagnostics, "Delete Request Failed", apiReq, apiResp)
+		return
+	}
+
+	addStatusMessageWarnings(&resp.Diagnostics, apiReq, apiResp, statusFilters)
+
 	tflog.Debug(ctx, "Resource deleted", map[string]interface{}{
-		"endpoint":    data.Endpoint.ValueString(),
-		"method":      data.Method.ValueString(),
+		"path":        apiReq.URL,
+		"method":      apiReq.Method,
 		"status_code": apiResp.StatusCode,
 		"success":     apiResp.Success,
 	})
 }
 
-func (r *CustomAPIResource) buildAPIRequest(data CustomAPIResourceModel) *clienttypes.CustomAPIRequest {
+// ImportState accepts "read_path|id" or "read_path|id|org_id". The object is
+// then refreshed through Read, which populates the remaining state. endpoint
+// is left unset so the configured value is adopted without a replacement.
+func (r *CustomAPIResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.Split(req.ID, "|")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Expected an import ID of the form read_path|id or read_path|id|org_id, got: %q", req.ID),
+		)
+		return
+	}
+
+	readPath := parts[0]
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("read_path"), readPath)...)
+
+	if len(parts) == 3 && parts[2] != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("org_id"), parts[2])...)
+	}
+
+	tflog.Debug(ctx, "Resource imported", map[string]interface{}{
+		"id":        parts[1],
+		"read_path": readPath,
+	})
+}
+
+// apiOperation is the method and path template used for one lifecycle step.
+type apiOperation struct {
+	method string
+	path   string
+}
+
+func (r *CustomAPIResource) createOperation(data CustomAPIResourceModel) apiOperation {
+	return apiOperation{
+		method: stringOrDefault(data.CreateMethod, stringOrDefault(data.Method, "POST")),
+		path:   stringOrDefault(data.CreatePath, data.Endpoint.ValueString()),
+	}
+}
+
+func (r *CustomAPIResource) readOperation(data CustomAPIResourceModel) apiOperation {
+	return apiOperation{
+		method: stringOrDefault(data.ReadMethod, "GET"),
+		path:   stringOrDefault(data.ReadPath, ""),
+	}
+}
+
+func (r *CustomAPIResource) updateOperation(data CustomAPIResourceModel) apiOperation {
+	objectPath := stringOrDefault(data.UpdatePath, stringOrDefault(data.ReadPath, ""))
+	if objectPath == "" {
+		// Without an object path the only thing we can do is replay the create call.
+		return r.createOperation(data)
+	}
+
+	defaultMethod := "PUT"
+	if stringOrDefault(data.UpdateStrategy, updateStrategyPut) != updateStrategyPut {
+		defaultMethod = "PATCH"
+	}
+
+	return apiOperation{
+		method: stringOrDefault(data.UpdateMethod, defaultMethod),
+		path:   objectPath,
+	}
+}
+
+func (r *CustomAPIResource) destroyOperation(data CustomAPIResourceModel) apiOperation {
+	return apiOperation{
+		method: stringOrDefault(data.DestroyMethod, "DELETE"),
+		path:   stringOrDefault(data.DestroyPath, stringOrDefault(data.ReadPath, "")),
+	}
+}
+
+// resolveID extracts the server-side identifier of a newly created object.
+func (r *CustomAPIResource) resolveID(data CustomAPIResourceModel, apiResp *clienttypes.CustomAPIResponse) (string, bool) {
+	if value, ok := lookupResponsePath(apiResp.Body, stringOrDefault(data.IDAttribute, "id")); ok {
+		if id, ok := scalarToString(value); ok {
+			return id, true
+		}
+	}
+
+	location := apiResp.Headers["Location"]
+	if location == "" {
+		return "", false
+	}
+
+	if parsed, err := url.Parse(location); err == nil {
+		location = parsed.Path
+	}
+
+	id := urlpath.Base(strings.TrimRight(location, "/"))
+	if id == "." || id == "/" {
+		return "", false
+	}
+
+	if unescaped, err := url.PathUnescape(id); err == nil {
+		id = unescaped
+	}
+	return id, true
+}
+
+func (r *CustomAPIResource) buildAPIRequest(data CustomAPIResourceModel, op apiOperation, withBody bool) (*clienttypes.CustomAPIRequest, error) {
 	headers := make(map[string]string)
 	for key, value := range data.Headers {
 		headers[key] = value.ValueString()
@@ -252,29 +847,138 @@ func (r *CustomAPIResource) buildAPIRequest(data CustomAPIResourceModel) *client
 	}
 
 	apiReq := &clienttypes.CustomAPIRequest{
-		Method:      data.Method.ValueString(),
-		URL:         data.Endpoint.ValueString(),
-		Headers:     headers,
-		QueryParams: queryParams,
+		Method:              strings.ToUpper(op.method),
+		URL:                 expandPath(op.path, data, r.orgID(data)),
+		Headers:             headers,
+		QueryParams:         queryParams,
+		ExpectedStatusCodes: int64sToInts(data.ExpectedStatusCodes),
 	}
 
-	if !data.Body.IsNull() && !data.Body.IsUnknown() {
-		apiReq.Body = []byte(data.Body.ValueString())
+	if withBody {
+		body, err := requestBody(data)
+		if err != nil {
+			return nil, err
+		}
+		apiReq.Body = body
 	}
 
+	// A configured org_id overrides the provider default; an empty one sends
+	// no organization header.
 	if !data.OrgID.IsNull() && !data.OrgID.IsUnknown() {
-		apiReq.Headers["current-organization"] = data.OrgID.ValueString()
+		apiReq.Headers[client.OrganizationHeader] = data.OrgID.ValueString()
 	}
 
-	return apiReq
+	return apiReq, nil
+}
+
+// orgID returns the resource's org_id, or the provider default when unset.
+func (r *CustomAPIResource) orgID(data CustomAPIResourceModel) string {
+	if !data.OrgID.IsNull() && !data.OrgID.IsUnknown() {
+		return data.OrgID.ValueString()
+	}
+	return r.client.DefaultOrgID()
+}
+
+// requestBody returns the configured body, encoding body_json when it is set.
+func requestBody(data CustomAPIResourceModel) ([]byte, error) {
+	if !data.BodyJSON.IsNull() && !data.BodyJSON.IsUnknown() && !data.BodyJSON.IsUnderlyingValueNull() {
+		return dynamicToJSON(data.BodyJSON)
+	}
+
+	if !data.Body.IsNull() && !data.Body.IsUnknown() {
+		return []byte(data.Body.ValueString()), nil
+	}
+
+	return nil, nil
 }
 
 func (r *CustomAPIResource) updateModelFromResponse(data *CustomAPIResourceModel, apiResp *clienttypes.CustomAPIResponse) {
-	data.Response = types.StringValue(string(apiResp.Body))
+	data.Response = NewNormalizedJSONValue(string(apiResp.Body))
+	data.ResponseJSON = jsonToDynamic(apiResp.Body)
 	data.StatusCode = types.Int64Value(int64(apiResp.StatusCode))
 	data.Success = types.BoolValue(apiResp.Success)
-	
+
 	if !apiResp.Success {
 		data.Error = types.StringValue(apiResp.Error)
+	} else {
+		data.Error = types.StringNull()
+	}
+
+	envelope := unwrapEnvelope(data.UnwrapEnvelope, apiResp)
+	data.Data = envelope.Data
+	data.RequestID = envelope.RequestID
+	data.OperationID = envelope.OperationID
+	data.APIStatus = envelope.APIStatus
+	data.Message = envelope.Message
+}
+
+// reconcileDrift replaces the body in state with the remote values of its keys
+// when detect_drift is enabled, so the next plan shows and reverts the change.
+func (r *CustomAPIResource) reconcileDrift(ctx context.Context, data *CustomAPIResourceModel, apiResp *clienttypes.CustomAPIResponse) error {
+	if !data.DetectDrift.ValueBool() {
+		return nil
+	}
+
+	if !data.BodyJSON.IsNull() && !data.BodyJSON.IsUnderlyingValueNull() {
+		configured, err := dynamicToJSON(data.BodyJSON)
+		if err != nil {
+			return err
+		}
+
+		if projected, drifted := detectBodyDrift(configured, apiResp, data.IgnoreFields); drifted {
+			tflog.Info(ctx, "Remote object drifted from body_json", map[string]interface{}{
+				"id": data.ID.ValueString(),
+			})
+			data.BodyJSON = jsonToDynamic(projected)
+		}
+		return nil
+	}
+
+	if data.Body.IsNull() {
+		return nil
+	}
+
+	if projected, drifted := detectBodyDrift([]byte(data.Body.ValueString()), apiResp, data.IgnoreFields); drifted {
+		tflog.Info(ctx, "Remote object drifted from body", map[string]interface{}{
+			"id": data.ID.ValueString(),
+		})
+		data.Body = NewNormalizedJSONValue(string(projected))
+	}
+	return nil
+}
+
+var defaultGoneStatusCodes = []int{404, 410}
+
+// isGoneStatus reports whether a read status code means the object was deleted out-of-band.
+func isGoneStatus(data CustomAPIResourceModel, statusCode int) bool {
+	if data.GoneStatusCodes == nil {
+		for _, code := range defaultGoneStatusCodes {
+			if code == statusCode {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, code := range data.GoneStatusCodes {
+		if code.ValueInt64() == int64(statusCode) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandPath substitutes the {id} and {org_id} placeholders in a path template.
+func expandPath(template string, data CustomAPIResourceModel, orgID string) string {
+	return strings.NewReplacer(
+		"{id}", url.PathEscape(data.ID.ValueString()),
+		"{org_id}", url.PathEscape(orgID),
+	).Replace(template)
+}
+
+func stringOrDefault(value types.String, fallback string) string {
+	if value.IsNull() || value.IsUnknown() || value.ValueString() == "" {
+		return fallback
 	}
+	return value.ValueString()
 }
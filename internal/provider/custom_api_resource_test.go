@@ -0,0 +1,284 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"terraform-provider-customapi/go-customapi/client"
+)
+
+func TestImportThenPlanDoesNotReplace(t *testing.T) {
+	cases := []struct {
+		name     string
+		importID string
+		orgID    tftypes.Value
+	}{
+		{name: "with org", importID: "/api/users/{id}/details|42|org-1", orgID: tftypes.NewValue(tftypes.String, "org-1")},
+		{name: "without org", importID: "/api/users/{id}/details|42", orgID: tftypes.NewValue(tftypes.String, nil)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := testProviderServer(t)
+			s := testResourceSchema(t)
+			objectType := s.Type().TerraformType(ctx)
+
+			importResp, err := server.ImportResourceState(ctx, &tfprotov6.ImportResourceStateRequest{
+				TypeName: "customapi_resource",
+				ID:       tc.importID,
+			})
+			if err != nil {
+				t.Fatalf("ImportResourceState: %v", err)
+			}
+			testFailOnDiagnostics(t, importResp.Diagnostics)
+
+			if len(importResp.ImportedResources) != 1 {
+				t.Fatalf("imported %d resources, want 1", len(importResp.ImportedResources))
+			}
+
+			prior, err := importResp.ImportedResources[0].State.Unmarshal(objectType)
+			if err != nil {
+				t.Fatalf("imported state: %v", err)
+			}
+
+			config := testObjectValue(t, s, map[string]tftypes.Value{
+				"endpoint":  tftypes.NewValue(tftypes.String, "/api/users"),
+				"read_path": tftypes.NewValue(tftypes.String, "/api/users/{id}/details"),
+				"org_id":    tc.orgID,
+				"body":      tftypes.NewValue(tftypes.String, `{"name":"alice"}`),
+			})
+
+			planResp, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+				TypeName:         "customapi_resource",
+				PriorState:       testDynamicValue(t, prior),
+				ProposedNewState: testDynamicValue(t, testProposedNewState(t, s, prior, config)),
+				Config:           testDynamicValue(t, config),
+			})
+			if err != nil {
+				t.Fatalf("PlanResourceChange: %v", err)
+			}
+			testFailOnDiagnostics(t, planResp.Diagnostics)
+
+			if len(planResp.RequiresReplace) > 0 {
+				t.Errorf("plan after import requires replacement of %v", planResp.RequiresReplace)
+			}
+
+			planned, err := planResp.PlannedState.Unmarshal(objectType)
+			if err != nil {
+				t.Fatalf("planned state: %v", err)
+			}
+			var plannedValues map[string]tftypes.Value
+			if err := planned.As(&plannedValues); err != nil {
+				t.Fatalf("planned state: %v", err)
+			}
+			if !plannedValues["org_id"].Equal(tc.orgID) {
+				t.Errorf("planned org_id = %s, want %s", plannedValues["org_id"], tc.orgID)
+			}
+		})
+	}
+}
+
+func TestBuildAPIRequestUpperCasesMethod(t *testing.T) {
+	r := &CustomAPIResource{client: client.NewCustomAPIClient(&client.AuthConfig{AuthToken: "token"}, "https://api.example.com")}
+	data := CustomAPIResourceModel{
+		Endpoint: types.StringValue("/api/users"),
+		Method:   types.StringValue("post"),
+	}
+
+	apiReq, err := r.buildAPIRequest(data, r.createOperation(data), false)
+	if err != nil {
+		t.Fatalf("buildAPIRequest: %v", err)
+	}
+	if apiReq.Method != "POST" {
+		t.Errorf("method = %q, want POST", apiReq.Method)
+	}
+}
+
+func TestModifyPlanDefaultOrgID(t *testing.T) {
+	ctx := context.Background()
+	s := testResourceSchema(t)
+
+	apiClient := client.NewCustomAPIClient(&client.AuthConfig{AuthToken: "token"}, "https://api.example.com")
+	apiClient.SetDefaultOrgID("org-b")
+	r := &CustomAPIResource{client: apiClient}
+
+	stringValue := func(value interface{}) tftypes.Value {
+		return tftypes.NewValue(tftypes.String, value)
+	}
+
+	cases := []struct {
+		name        string
+		configured  tftypes.Value
+		prior       tftypes.Value
+		create      bool
+		want        string
+		wantNull    bool
+		wantReplace bool
+	}{
+		{name: "create records default", configured: stringValue(nil), create: true, want: "org-b"},
+		{name: "unchanged default", configured: stringValue(nil), prior: stringValue("org-b"), want: "org-b"},
+		{name: "changed default replaces", configured: stringValue(nil), prior: stringValue("org-a"), want: "org-b", wantReplace: true},
+		{name: "imported without org stays unset", configured: stringValue(nil), prior: stringValue(nil), wantNull: true},
+		{name: "configured override", configured: stringValue("org-a"), prior: stringValue("org-a"), want: "org-a"},
+		{name: "configured empty", configured: stringValue(""), prior: stringValue(""), want: ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			values := map[string]tftypes.Value{
+				"endpoint": stringValue("/api/users"),
+				"org_id":   tc.configured,
+			}
+			config := testObjectValue(t, s, values)
+
+			state := tftypes.NewValue(s.Type().TerraformType(ctx), nil)
+			planned := tc.configured
+			if !tc.create {
+				state = testObjectValue(t, s, map[string]tftypes.Value{
+					"endpoint": stringValue("/api/users"),
+					"org_id":   tc.prior,
+				})
+				if tc.configured.IsNull() {
+					// UseStateForUnknown leaves a null prior value unknown.
+					planned = tc.prior
+					if tc.prior.IsNull() {
+						planned = stringValue(tftypes.UnknownValue)
+					}
+				}
+			} else if tc.configured.IsNull() {
+				planned = stringValue(tftypes.UnknownValue)
+			}
+			values["org_id"] = planned
+			plan := testObjectValue(t, s, values)
+
+			req := resource.ModifyPlanRequest{
+				Config: tfsdk.Config{Schema: s, Raw: config},
+				Plan:   tfsdk.Plan{Schema: s, Raw: plan},
+				State:  tfsdk.State{Schema: s, Raw: state},
+			}
+			resp := &resource.ModifyPlanResponse{Plan: req.Plan}
+
+			r.ModifyPlan(ctx, req, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("ModifyPlan: %v", resp.Diagnostics)
+			}
+
+			var got types.String
+			resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("org_id"), &got)...)
+			if got.IsNull() != tc.wantNull || got.ValueString() != tc.want || got.IsUnknown() {
+				t.Errorf("planned org_id = %s, want %q", got, tc.want)
+			}
+
+			replaced := resp.RequiresReplace.Contains(path.Root("org_id"))
+			if replaced != tc.wantReplace {
+				t.Errorf("requires replace = %v, want %v", replaced, tc.wantReplace)
+			}
+		})
+	}
+}
+
+func TestModifyPlanUnknownCollections(t *testing.T) {
+	ctx := context.Background()
+	s := testResourceSchema(t)
+	objectType := s.Type().TerraformType(ctx).(tftypes.Object)
+	r := &CustomAPIResource{}
+
+	stringList := func(values ...string) tftypes.Value {
+		elements := make([]tftypes.Value, 0, len(values))
+		for _, value := range values {
+			elements = append(elements, tftypes.NewValue(tftypes.String, value))
+		}
+		return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, elements)
+	}
+
+	cases := []struct {
+		name            string
+		unknown         string
+		replaceOnChange tftypes.Value
+		plannedBody     string
+		wantReplace     bool
+	}{
+		{name: "unknown headers", unknown: "headers", replaceOnChange: stringList()},
+		{name: "unknown query params with replace_on_change", unknown: "query_params", replaceOnChange: stringList("name"), plannedBody: `{"name":"b"}`, wantReplace: true},
+		{name: "unknown ignore_fields", unknown: "ignore_fields", replaceOnChange: stringList("name"), plannedBody: `{"name":"a"}`},
+		{name: "unknown replace_on_change", unknown: "replace_on_change", replaceOnChange: stringList("name"), plannedBody: `{"name":"b"}`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			prior := testObjectValue(t, s, map[string]tftypes.Value{
+				"id":                tftypes.NewValue(tftypes.String, "1"),
+				"endpoint":          tftypes.NewValue(tftypes.String, "/api/users"),
+				"body":              tftypes.NewValue(tftypes.String, `{"name":"a"}`),
+				"replace_on_change": tc.replaceOnChange,
+			})
+
+			plannedBody := tc.plannedBody
+			if plannedBody == "" {
+				plannedBody = `{"name":"a"}`
+			}
+			values := map[string]tftypes.Value{
+				"id":                tftypes.NewValue(tftypes.String, "1"),
+				"endpoint":          tftypes.NewValue(tftypes.String, "/api/users"),
+				"body":              tftypes.NewValue(tftypes.String, plannedBody),
+				"replace_on_change": tc.replaceOnChange,
+			}
+			values[tc.unknown] = tftypes.NewValue(objectType.AttributeTypes[tc.unknown], tftypes.UnknownValue)
+			plan := testObjectValue(t, s, values)
+
+			req := resource.ModifyPlanRequest{
+				Config: tfsdk.Config{Schema: s, Raw: plan},
+				Plan:   tfsdk.Plan{Schema: s, Raw: plan},
+				State:  tfsdk.State{Schema: s, Raw: prior},
+			}
+			resp := &resource.ModifyPlanResponse{Plan: req.Plan}
+
+			r.ModifyPlan(ctx, req, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("ModifyPlan: %v", resp.Diagnostics)
+			}
+
+			if replaced := resp.RequiresReplace.Contains(path.Root("body")); replaced != tc.wantReplace {
+				t.Errorf("requires replace = %v, want %v", replaced, tc.wantReplace)
+			}
+		})
+	}
+}
+
+func TestChangedBodyPaths(t *testing.T) {
+	cases := []struct {
+		name    string
+		prior   string
+		planned string
+		paths   []string
+		want    []string
+	}{
+		{name: "unchanged", prior: `{"name": "a"}`, planned: `{"name": "a"}`, paths: []string{"name"}},
+		{name: "equivalent numbers", prior: `{"size": 1.0}`, planned: `{"size": 1}`, paths: []string{"size"}},
+		{name: "large integer changed", prior: `{"id": 12345678901234567890}`, planned: `{"id": 12345678901234567891}`, paths: []string{"id"}, want: []string{"id"}},
+		{name: "nested change", prior: `{"spec": {"zone": "a"}}`, planned: `{"spec": {"zone": "b"}}`, paths: []string{"spec.zone", "name"}, want: []string{"spec.zone"}},
+		{name: "added key", prior: `{}`, planned: `{"name": "a"}`, paths: []string{"name"}, want: []string{"name"}},
+		{name: "unlisted change", prior: `{"name": "a"}`, planned: `{"name": "b"}`, paths: []string{"size"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := changedBodyPaths([]byte(tc.prior), []byte(tc.planned), tc.paths)
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("got %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
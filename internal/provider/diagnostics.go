@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	clienttypes "terraform-provider-customapi/go-customapi/client/types"
+)
+
+const responseExcerptLength = 512
+
+// addResponseError reports an API response with an unexpected status code,
+// including the envelope correlation IDs so failures can be traced in backend logs.
+func addResponseError(diags *diag.Diagnostics, summary string, apiReq *clienttypes.CustomAPIRequest, apiResp *clienttypes.CustomAPIResponse) {
+	apiErr := apiResp.APIError
+	if apiErr == nil {
+		apiErr = clienttypes.NewAPIError(apiResp.StatusCode, apiResp.Envelope)
+	}
+
+	var detail strings.Builder
+
+	fmt.Fprintf(&detail, "%s %s returned status %d: %s\n", apiReq.Method, apiReq.URL, apiErr.Code, apiErr.Message)
+	writeDetailField(&detail, "Status", apiErr.Status)
+	writeDetailField(&detail, "Details", apiErr.Details)
+	writeDetailField(&detail, "Request ID", apiErr.RequestId)
+	writeDetailField(&detail, "Operation ID", apiErr.OperationId)
+
+	if len(apiErr.StatusMessages) > 0 {
+		detail.WriteString("Status messages:\n")
+		for _, message := range apiErr.StatusMessages {
+			fmt.Fprintf(&detail, "  - %s\n", message)
+		}
+	}
+
+	if len(apiResp.Body) > 0 {
+		fmt.Fprintf(&detail, "\nResponse body:\n%s", excerpt(string(apiResp.Body), responseExcerptLength))
+	}
+
+	diags.AddError(summary, detail.String())
+}
+
+// compileStatusMessageFilters compiles the suppress_status_messages patterns.
+// It runs before a request is sent, so an invalid pattern cannot fail an
+// operation whose request already succeeded.
+func compileStatusMessageFilters(diags *diag.Diagnostics, suppress []types.String) ([]*regexp.Regexp, bool) {
+	patterns := make([]*regexp.Regexp, 0, len(suppress))
+	for _, value := range suppress {
+		pattern, err := regexp.Compile(value.ValueString())
+		if err != nil {
+			diags.AddError(
+				"Invalid Status Message Filter",
+				fmt.Sprintf("suppress_status_messages entry %q is not a valid regular expression: %v", value.ValueString(), err),
+			)
+			return nil, false
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, true
+}
+
+// addStatusMessageWarnings surfaces the envelope status_messages of a successful
+// response as warnings, skipping messages that match any suppress pattern.
+func addStatusMessageWarnings(diags *diag.Diagnostics, apiReq *clienttypes.CustomAPIRequest, apiResp *clienttypes.CustomAPIResponse, patterns []*regexp.Regexp) {
+	if !apiResp.Success || apiResp.Envelope == nil || len(apiResp.Envelope.StatusMessages) == 0 {
+		return
+	}
+
+	for _, message := range apiResp.Envelope.StatusMessages {
+		if matchesAny(patterns, message) {
+			continue
+		}
+
+		detail := fmt.Sprintf("%s %s: %s", apiReq.Method, apiReq.URL, message)
+		if apiResp.Envelope.RequestId != "" {
+			detail += fmt.Sprintf("\nRequest ID: %s", apiResp.Envelope.RequestId)
+		}
+		diags.AddWarning("API Status Message", detail)
+	}
+}
+
+func matchesAny(patterns []*regexp.Regexp, value string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeDetailField(detail *strings.Builder, name string, value string) {
+	if value != "" {
+		fmt.Fprintf(detail, "%s: %s\n", name, value)
+	}
+}
+
+// excerpt truncates value to at most length bytes, backing up to a rune
+// boundary so a multi-byte character is never split.
+func excerpt(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+
+	end := length
+	for end > 0 && !utf8.RuneStart(value[end]) {
+		end--
+	}
+	return value[:end] + "... (truncated)"
+}
+
+func int64sToInts(values []types.Int64) []int {
+	if len(values) == 0 {
+		return nil
+	}
+
+	ints := make([]int, 0, len(values))
+	for _, value := range values {
+		ints = append(ints, int(value.ValueInt64()))
+	}
+	return ints
+}
+
+func failOnError(value types.Bool) bool {
+	return value.IsNull() || value.IsUnknown() || value.ValueBool()
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	clienttypes "terraform-provider-customapi/go-customapi/client/types"
+)
+
+// detectBodyDrift projects the remote object onto the keys of the configured
+// body. It returns the projected body and true when the remote object no
+// longer matches what was configured. Keys missing from the remote object and
+// paths listed in ignoreFields are never reported as drift.
+func detectBodyDrift(configured []byte, apiResp *clienttypes.CustomAPIResponse, ignoreFields []types.String) ([]byte, bool) {
+	desired, ok := decodeJSONObject(configured)
+	if !ok {
+		return nil, false
+	}
+
+	remote, ok := remoteObject(apiResp)
+	if !ok {
+		return nil, false
+	}
+
+	ignored := make(map[string]bool, len(ignoreFields))
+	for _, field := range ignoreFields {
+		ignored[field.ValueString()] = true
+	}
+
+	projected := projectObject(desired, remote, "", ignored)
+	if jsonValuesEqual(projected, desired) {
+		return nil, false
+	}
+
+	body, err := json.Marshal(projected)
+	if err != nil {
+		return nil, false
+	}
+	return body, true
+}
+
+// remoteObject returns the object held by the server, taken from the data
+// field when the response is wrapped in the APIResponse envelope.
+func remoteObject(apiResp *clienttypes.CustomAPIResponse) (map[string]interface{}, bool) {
+	if apiResp.Envelope != nil && len(apiResp.Envelope.Data) > 0 {
+		if object, ok := decodeJSONObject(apiResp.Envelope.Data); ok {
+			return object, true
+		}
+	}
+
+	return decodeJSONObject(apiResp.Body)
+}
+
+// decodeJSONObject decodes a JSON object, keeping numbers exact.
+func decodeJSONObject(body []byte) (map[string]interface{}, bool) {
+	value, err := decodeJSON(body)
+	if err != nil {
+		return nil, false
+	}
+
+	object, ok := value.(map[string]interface{})
+	return object, ok
+}
+
+func projectObject(desired map[string]interface{}, remote map[string]interface{}, prefix string, ignored map[string]bool) map[string]interface{} {
+	projected := make(map[string]interface{}, len(desired))
+
+	for key, desiredValue := range desired {
+		fieldPath := key
+		if prefix != "" {
+			fieldPath = prefix + "." + key
+		}
+
+		remoteValue, ok := remote[key]
+		if !ok || ignored[fieldPath] {
+			projected[key] = desiredValue
+			continue
+		}
+
+		desiredObject, desiredIsObject := desiredValue.(map[string]interface{})
+		remoteObject, remoteIsObject := remoteValue.(map[string]interface{})
+		if desiredIsObject && remoteIsObject {
+			projected[key] = projectObject(desiredObject, remoteObject, fieldPath, ignored)
+			continue
+		}
+
+		projected[key] = remoteValue
+	}
+
+	return projected
+}
@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	clienttypes "terraform-provider-customapi/go-customapi/client/types"
+)
+
+func TestProjectObject(t *testing.T) {
+	cases := []struct {
+		name    string
+		desired string
+		remote  string
+		ignored []string
+		want    string
+	}{
+		{
+			name:    "unchanged",
+			desired: `{"name": "a", "size": 1}`,
+			remote:  `{"name": "a", "size": 1, "created_at": "2026-01-01"}`,
+			want:    `{"name": "a", "size": 1}`,
+		},
+		{
+			name:    "changed scalar",
+			desired: `{"name": "a"}`,
+			remote:  `{"name": "b"}`,
+			want:    `{"name": "b"}`,
+		},
+		{
+			name:    "missing remote key keeps desired",
+			desired: `{"name": "a", "secret": "s"}`,
+			remote:  `{"name": "a"}`,
+			want:    `{"name": "a", "secret": "s"}`,
+		},
+		{
+			name:    "nested objects are projected",
+			desired: `{"spec": {"enabled": true}}`,
+			remote:  `{"spec": {"enabled": false, "extra": 1}}`,
+			want:    `{"spec": {"enabled": false}}`,
+		},
+		{
+			name:    "ignored nested field",
+			desired: `{"spec": {"enabled": true, "replicas": 2}}`,
+			remote:  `{"spec": {"enabled": false, "replicas": 3}}`,
+			ignored: []string{"spec.replicas"},
+			want:    `{"spec": {"enabled": false, "replicas": 2}}`,
+		},
+		{
+			name:    "arrays are replaced",
+			desired: `{"tags": ["a", "b"]}`,
+			remote:  `{"tags": ["b"]}`,
+			want:    `{"tags": ["b"]}`,
+		},
+		{
+			name:    "remote null",
+			desired: `{"parent": "p"}`,
+			remote:  `{"parent": null}`,
+			want:    `{"parent": null}`,
+		},
+		{
+			name:    "object replaced by scalar",
+			desired: `{"spec": {"enabled": true}}`,
+			remote:  `{"spec": "legacy"}`,
+			want:    `{"spec": "legacy"}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			desired, _ := decodeJSONObject([]byte(tc.desired))
+			remote, _ := decodeJSONObject([]byte(tc.remote))
+			want, _ := decodeJSONObject([]byte(tc.want))
+
+			ignored := make(map[string]bool, len(tc.ignored))
+			for _, field := range tc.ignored {
+				ignored[field] = true
+			}
+
+			if got := projectObject(desired, remote, "", ignored); !jsonValuesEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestDetectBodyDrift(t *testing.T) {
+	cases := []struct {
+		name      string
+		body      string
+		response  string
+		envelope  *clienttypes.APIResponse
+		ignore    []types.String
+		wantDrift bool
+		wantBody  string
+	}{
+		{
+			name:     "no drift with large id",
+			body:     `{"owner_id": 12345678901234567890}`,
+			response: `{"owner_id": 12345678901234567890, "id": "x"}`,
+		},
+		{
+			name:      "drift keeps large ids exact",
+			body:      `{"owner_id": 12345678901234567890, "name": "a"}`,
+			response:  `{"owner_id": 12345678901234567890, "name": "b"}`,
+			wantDrift: true,
+			wantBody:  `{"owner_id":12345678901234567890,"name":"b"}`,
+		},
+		{
+			name:      "envelope data",
+			body:      `{"name": "a"}`,
+			response:  `{"status": "ok", "data": {"name": "b"}}`,
+			envelope:  &clienttypes.APIResponse{Status: "ok", Data: []byte(`{"name": "b"}`)},
+			wantDrift: true,
+			wantBody:  `{"name":"b"}`,
+		},
+		{
+			name:     "ignored field",
+			body:     `{"name": "a"}`,
+			response: `{"name": "b"}`,
+			ignore:   []types.String{types.StringValue("name")},
+		},
+		{
+			name:     "non-object response",
+			body:     `{"name": "a"}`,
+			response: `["a"]`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiResp := &clienttypes.CustomAPIResponse{Body: []byte(tc.response), Envelope: tc.envelope}
+
+			body, drift := detectBodyDrift([]byte(tc.body), apiResp, tc.ignore)
+			if drift != tc.wantDrift {
+				t.Fatalf("drift = %v, want %v (body %s)", drift, tc.wantDrift, body)
+			}
+			if drift && !jsonSemanticallyEqual(string(body), tc.wantBody) {
+				t.Errorf("body = %s, want %s", body, tc.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// dynamicToJSON encodes a Terraform value, typically the underlying value of a
+// dynamic attribute, as JSON.
+func dynamicToJSON(value attr.Value) ([]byte, error) {
+	native, err := attrValueToNative(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(native)
+}
+
+func attrValueToNative(value attr.Value) (interface{}, error) {
+	if value == nil || value.IsNull() {
+		return nil, nil
+	}
+
+	if value.IsUnknown() {
+		return nil, fmt.Errorf("cannot encode unknown value as JSON")
+	}
+
+	switch v := value.(type) {
+	case basetypes.DynamicValue:
+		return attrValueToNative(v.UnderlyingValue())
+	case basetypes.StringValue:
+		return v.ValueString(), nil
+	case basetypes.BoolValue:
+		return v.ValueBool(), nil
+	case basetypes.Int64Value:
+		return v.ValueInt64(), nil
+	case basetypes.Float64Value:
+		return v.ValueFloat64(), nil
+	case basetypes.NumberValue:
+		return bigFloatToJSONNumber(v.ValueBigFloat()), nil
+	case basetypes.ListValue:
+		return elementsToNative(v.Elements())
+	case basetypes.SetValue:
+		return elementsToNative(v.Elements())
+	case basetypes.TupleValue:
+		return elementsToNative(v.Elements())
+	case basetypes.MapValue:
+		return attributesToNative(v.Elements())
+	case basetypes.ObjectValue:
+		return attributesToNative(v.Attributes())
+	default:
+		return nil, fmt.Errorf("cannot encode %s as JSON", value.Type(context.Background()))
+	}
+}
+
+// jsonNumberPrecision matches the precision Terraform uses for numbers, so
+// integers far beyond 2^64 decode without loss.
+const jsonNumberPrecision = 512
+
+// bigFloatToJSONNumber renders exactly representable integers without an
+// exponent, so identifiers such as 12345678901234567890 are sent as configured.
+func bigFloatToJSONNumber(value *big.Float) json.Number {
+	if value.IsInt() && value.MantExp(nil) <= int(value.Prec()) {
+		return json.Number(value.Text('f', 0))
+	}
+	return json.Number(value.Text('g', -1))
+}
+
+func elementsToNative(elements []attr.Value) (interface{}, error) {
+	native := make([]interface{}, 0, len(elements))
+	for _, element := range elements {
+		value, err := attrValueToNative(element)
+		if err != nil {
+			return nil, err
+		}
+		native = append(native, value)
+	}
+	return native, nil
+}
+
+func attributesToNative(attributes map[string]attr.Value) (interface{}, error) {
+	native := make(map[string]interface{}, len(attributes))
+	for key, attribute := range attributes {
+		value, err := attrValueToNative(attribute)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		native[key] = value
+	}
+	return native, nil
+}
+
+// jsonToDynamic decodes a JSON document into a dynamic value. Objects become
+// Terraform objects and arrays become tuples so mixed element types survive.
+// A body that is empty or not valid JSON yields a null value.
+func jsonToDynamic(body []byte) types.Dynamic {
+	if len(body) == 0 {
+		return types.DynamicNull()
+	}
+
+	value, err := decodeJSON(body)
+	if err != nil {
+		return types.DynamicNull()
+	}
+
+	if value == nil {
+		return types.DynamicNull()
+	}
+
+	return types.DynamicValue(nativeToAttrValue(value))
+}
+
+func nativeToAttrValue(value interface{}) attr.Value {
+	switch v := value.(type) {
+	case nil:
+		// JSON null has no type of its own, a null string is the closest fit.
+		return types.StringNull()
+	case string:
+		return types.StringValue(v)
+	case bool:
+		return types.BoolValue(v)
+	case json.Number:
+		number, _, err := big.ParseFloat(v.String(), 10, jsonNumberPrecision, big.ToNearestEven)
+		if err != nil {
+			return types.StringValue(v.String())
+		}
+		return types.NumberValue(number)
+	case []interface{}:
+		elementTypes := make([]attr.Type, 0, len(v))
+		elements := make([]attr.Value, 0, len(v))
+		for _, item := range v {
+			element := nativeToAttrValue(item)
+			elementTypes = append(elementTypes, element.Type(context.Background()))
+			elements = append(elements, element)
+		}
+		return types.TupleValueMust(elementTypes, elements)
+	case map[string]interface{}:
+		attributeTypes := make(map[string]attr.Type, len(v))
+		attributes := make(map[string]attr.Value, len(v))
+		for key, item := range v {
+			attribute := nativeToAttrValue(item)
+			attributeTypes[key] = attribute.Type(context.Background())
+			attributes[key] = attribute
+		}
+		return types.ObjectValueMust(attributeTypes, attributes)
+	default:
+		return types.StringValue(fmt.Sprintf("%v", v))
+	}
+}
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestJSONToDynamicRoundTrip(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+	}{
+		{name: "string", body: `"hello"`},
+		{name: "bool", body: `true`},
+		{name: "integer", body: `42`},
+		{name: "negative float", body: `-1.5`},
+		{name: "large integer", body: `{"id": 12345678901234567890}`},
+		{name: "max int64", body: `{"id": 9223372036854775807}`},
+		{name: "nested object", body: `{"name": "device", "spec": {"enabled": true, "ports": [80, 443]}}`},
+		{name: "mixed array", body: `[1, "two", {"three": 3}, [4]]`},
+		{name: "empty object", body: `{}`},
+		{name: "empty array", body: `[]`},
+		{name: "null member", body: `{"parent": null}`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			value := jsonToDynamic([]byte(tc.body))
+			if value.IsNull() {
+				t.Fatalf("jsonToDynamic returned null for %s", tc.body)
+			}
+
+			encoded, err := dynamicToJSON(value)
+			if err != nil {
+				t.Fatalf("dynamicToJSON: %v", err)
+			}
+
+			if !jsonSemanticallyEqual(tc.body, string(encoded)) {
+				t.Errorf("round trip changed the document: got %s, want %s", encoded, tc.body)
+			}
+		})
+	}
+}
+
+func TestJSONToDynamicNull(t *testing.T) {
+	for _, body := range []string{``, `null`, `not json`} {
+		if value := jsonToDynamic([]byte(body)); !value.IsNull() {
+			t.Errorf("jsonToDynamic(%q) = %s, want null", body, value)
+		}
+	}
+}
+
+func TestDynamicToJSON(t *testing.T) {
+	cases := []struct {
+		name  string
+		value attr.Value
+		want  string
+	}{
+		{name: "null", value: types.DynamicNull(), want: `null`},
+		{name: "int64", value: types.DynamicValue(types.Int64Value(7)), want: `7`},
+		{
+			name: "list",
+			value: types.DynamicValue(types.ListValueMust(types.StringType, []attr.Value{
+				types.StringValue("a"), types.StringValue("b"),
+			})),
+			want: `["a", "b"]`,
+		},
+		{
+			name: "map",
+			value: types.DynamicValue(types.MapValueMust(types.BoolType, map[string]attr.Value{
+				"enabled": types.BoolValue(true),
+			})),
+			want: `{"enabled": true}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded, err := dynamicToJSON(tc.value)
+			if err != nil {
+				t.Fatalf("dynamicToJSON: %v", err)
+			}
+			if !jsonSemanticallyEqual(tc.want, string(encoded)) {
+				t.Errorf("got %s, want %s", encoded, tc.want)
+			}
+		})
+	}
+
+	if _, err := dynamicToJSON(types.DynamicUnknown()); err == nil {
+		t.Error("expected an error encoding an unknown value")
+	}
+}
+
+func TestJSONToDynamicPreservesIntegers(t *testing.T) {
+	for _, body := range []string{
+		`12345678901234567890`,
+		`9223372036854775807`,
+		`123456789012345678901234567890`,
+		`-42`,
+	} {
+		encoded, err := dynamicToJSON(jsonToDynamic([]byte(body)))
+		if err != nil {
+			t.Fatalf("dynamicToJSON: %v", err)
+		}
+		if string(encoded) != body {
+			t.Errorf("got %s, want %s", encoded, body)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	clienttypes "terraform-provider-customapi/go-customapi/client/types"
+)
+
+// envelopeValues holds the computed attributes exposed by unwrap_envelope.
+type envelopeValues struct {
+	Data        types.String
+	RequestID   types.String
+	OperationID types.String
+	APIStatus   types.String
+	Message     types.String
+}
+
+// unwrapEnvelope returns the envelope fields of a response, or nulls when
+// unwrapping is disabled or the body is not an envelope.
+func unwrapEnvelope(enabled types.Bool, apiResp *clienttypes.CustomAPIResponse) envelopeValues {
+	values := envelopeValues{
+		Data:        types.StringNull(),
+		RequestID:   types.StringNull(),
+		OperationID: types.StringNull(),
+		APIStatus:   types.StringNull(),
+		Message:     types.StringNull(),
+	}
+
+	if !enabled.ValueBool() || apiResp.Envelope == nil {
+		return values
+	}
+
+	envelope := apiResp.Envelope
+	if len(envelope.Data) > 0 {
+		values.Data = types.StringValue(string(envelope.Data))
+	}
+	values.RequestID = stringValueOrNull(envelope.RequestId)
+	values.OperationID = stringValueOrNull(envelope.OperationId)
+	values.APIStatus = stringValueOrNull(envelope.Status)
+	values.Message = stringValueOrNull(envelope.Message)
+
+	return values
+}
+
+func stringValueOrNull(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
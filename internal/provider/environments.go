@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// CustomAPIEnvironmentModel is a named profile selected with environment or
+// CUSTOMAPI_PROFILE. Its values apply when the provider block does not set them.
+type CustomAPIEnvironmentModel struct {
+	BaseURL   types.String `tfsdk:"base_url"`
+	AuthURL   types.String `tfsdk:"auth_url"`
+	IssuerURL types.String `tfsdk:"issuer_url"`
+	ClientID  types.String `tfsdk:"client_id"`
+	Audience  types.String `tfsdk:"audience"`
+	OrgID     types.String `tfsdk:"org_id"`
+}
+
+// environmentProfiles decodes the environments map, returning nil when it is
+// null or unknown.
+func environmentProfiles(ctx context.Context, value types.Map, diags *diag.Diagnostics) map[string]CustomAPIEnvironmentModel {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+
+	var environments map[string]CustomAPIEnvironmentModel
+	diags.Append(value.ElementsAs(ctx, &environments, false)...)
+	return environments
+}
+
+var knownEnvironments = []string{"qa", "staging", "prod"}
+
+func environmentsAttribute() schema.MapNestedAttribute {
+	return schema.MapNestedAttribute{
+		Optional:    true,
+		Description: "Named environment profiles, selected with environment or CUSTOMAPI_PROFILE",
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"base_url": schema.StringAttribute{
+					Optional:    true,
+					Description: "Base URL for the API",
+				},
+				"auth_url": schema.StringAttribute{
+					Optional:    true,
+					Description: "Authorization server URL; tokens are requested from <auth_url>/oauth/token",
+				},
+				"issuer_url": schema.StringAttribute{
+					Optional:    true,
+					Description: "OIDC issuer URL used to discover the token endpoint",
+				},
+				"client_id": schema.StringAttribute{
+					Optional:    true,
+					Description: "OAuth2 client ID",
+				},
+				"audience": schema.StringAttribute{
+					Optional:    true,
+					Description: "OAuth2 audience requested for the access token",
+				},
+				"org_id": schema.StringAttribute{
+					Optional:    true,
+					Description: "Default organization ID",
+				},
+			},
+		},
+	}
+}
+
+// isKnownEnvironment reports whether environment is a built-in environment or
+// a configured profile.
+func isKnownEnvironment(environment string, environments map[string]CustomAPIEnvironmentModel) bool {
+	if _, ok := environments[environment]; ok {
+		return true
+	}
+
+	for _, known := range knownEnvironments {
+		if environment == known {
+			return true
+		}
+	}
+	return false
+}
+
+// environmentNames lists the built-in environments followed by the configured profiles.
+func environmentNames(environments map[string]CustomAPIEnvironmentModel) []string {
+	names := append([]string{}, knownEnvironments...)
+
+	profiles := make([]string, 0, len(environments))
+	for name := range environments {
+		if containsName(knownEnvironments, name) {
+			continue
+		}
+		profiles = append(profiles, name)
+	}
+	sort.Strings(profiles)
+
+	return append(names, profiles...)
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func validateEnvironmentProfiles(environments map[string]CustomAPIEnvironmentModel, diags *diag.Diagnostics) {
+	for name, profile := range environments {
+		urls := map[string]types.String{
+			"base_url":   profile.BaseURL,
+			"auth_url":   profile.AuthURL,
+			"issuer_url": profile.IssuerURL,
+		}
+
+		for attribute, value := range urls {
+			if !isKnownString(value) {
+				continue
+			}
+
+			if err := validateBaseURL(value.ValueString()); err != nil {
+				diags.AddAttributeError(
+					path.Root("environments").AtMapKey(name).AtName(attribute),
+					"Invalid Environment URL",
+					fmt.Sprintf("%s %v", attribute, err),
+				)
+			}
+		}
+	}
+}
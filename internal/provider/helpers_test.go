@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func testResourceSchema(t *testing.T) schema.Schema {
+	t.Helper()
+
+	resp := &resource.SchemaResponse{}
+	NewCustomAPIResource().Schema(context.Background(), resource.SchemaRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("schema: %v", resp.Diagnostics)
+	}
+	return resp.Schema
+}
+
+// testObjectValue builds a value of the schema's object type from the given
+// attributes, leaving every other attribute null.
+func testObjectValue(t *testing.T, s schema.Schema, values map[string]tftypes.Value) tftypes.Value {
+	t.Helper()
+
+	objectType, ok := s.Type().TerraformType(context.Background()).(tftypes.Object)
+	if !ok {
+		t.Fatalf("schema type is not an object")
+	}
+
+	attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+			continue
+		}
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+
+	for name := range values {
+		if _, ok := objectType.AttributeTypes[name]; !ok {
+			t.Fatalf("unknown attribute %q", name)
+		}
+	}
+
+	return tftypes.NewValue(objectType, attributes)
+}
+
+func testProviderServer(t *testing.T) tfprotov6.ProviderServer {
+	t.Helper()
+
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	if err != nil {
+		t.Fatalf("provider server: %v", err)
+	}
+	return server
+}
+
+// testProposedNewState mimics Terraform's proposed new state: configured
+// values win, computed attributes left unset in config keep their prior value.
+func testProposedNewState(t *testing.T, s schema.Schema, prior tftypes.Value, config tftypes.Value) tftypes.Value {
+	t.Helper()
+
+	var priorValues, configValues map[string]tftypes.Value
+	if err := prior.As(&priorValues); err != nil {
+		t.Fatalf("prior state: %v", err)
+	}
+	if err := config.As(&configValues); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+
+	proposed := make(map[string]tftypes.Value, len(configValues))
+	for name, value := range configValues {
+		proposed[name] = value
+		if value.IsNull() && s.Attributes[name].IsComputed() {
+			proposed[name] = priorValues[name]
+		}
+	}
+
+	return tftypes.NewValue(config.Type(), proposed)
+}
+
+func testDynamicValue(t *testing.T, value tftypes.Value) *tfprotov6.DynamicValue {
+	t.Helper()
+
+	dynamicValue, err := tfprotov6.NewDynamicValue(value.Type(), value)
+	if err != nil {
+		t.Fatalf("dynamic value: %v", err)
+	}
+	return &dynamicValue
+}
+
+func testFailOnDiagnostics(t *testing.T, diagnostics []*tfprotov6.Diagnostic) {
+	t.Helper()
+
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("%s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+}
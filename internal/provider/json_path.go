@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	clienttypes "terraform-provider-customapi/go-customapi/client/types"
+)
+
+// decodeJSON unmarshals a JSON document keeping numbers as json.Number so
+// identifiers and large integers survive the round trip unchanged.
+func decodeJSON(body []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// splitJSONPath accepts either a JSON pointer ("/data/id") or a dotted path
+// ("data.id") and returns its segments.
+func splitJSONPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+
+	if strings.HasPrefix(path, "/") {
+		segments := strings.Split(path[1:], "/")
+		for i, segment := range segments {
+			segments[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+		}
+		return segments
+	}
+
+	return strings.Split(path, ".")
+}
+
+func lookupJSONPath(value interface{}, path string) (interface{}, bool) {
+	current := value
+	for _, segment := range splitJSONPath(path) {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			next, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+
+	if current == nil {
+		return nil, false
+	}
+	return current, true
+}
+
+// lookupResponsePath resolves path against the response body, falling back to
+// the data field of the APIResponse envelope when the path is not found at
+// the top level.
+func lookupResponsePath(body []byte, path string) (interface{}, bool) {
+	value, err := decodeJSON(body)
+	if err != nil {
+		return nil, false
+	}
+
+	if found, ok := lookupJSONPath(value, path); ok {
+		return found, true
+	}
+
+	var envelope clienttypes.APIResponse
+	if err := json.Unmarshal(body, &envelope); err != nil || len(envelope.Data) == 0 {
+		return nil, false
+	}
+
+	data, err := decodeJSON(envelope.Data)
+	if err != nil {
+		return nil, false
+	}
+	return lookupJSONPath(data, path)
+}
+
+// scalarToString renders a JSON scalar as a Terraform-friendly string.
+func scalarToString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, v != ""
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	default:
+		return fmt.Sprintf("%v", v), false
+	}
+}
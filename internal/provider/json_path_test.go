@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestLookupJSONPath(t *testing.T) {
+	document := `{
+		"id": 12345678901234567890,
+		"name": "device",
+		"data": {"id": "abc", "tags": ["a", "b"], "a/b": {"~c": true}},
+		"items": [{"id": 1}, {"id": 2}],
+		"empty": null
+	}`
+
+	value, err := decodeJSON([]byte(document))
+	if err != nil {
+		t.Fatalf("decodeJSON: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		path  string
+		want  interface{}
+		found bool
+	}{
+		{name: "top-level large number", path: "id", want: json.Number("12345678901234567890"), found: true},
+		{name: "dotted", path: "data.id", want: "abc", found: true},
+		{name: "pointer", path: "/data/id", want: "abc", found: true},
+		{name: "array index dotted", path: "items.1.id", want: json.Number("2"), found: true},
+		{name: "array index pointer", path: "/data/tags/0", want: "a", found: true},
+		{name: "escaped pointer", path: "/data/a~1b/~0c", want: true, found: true},
+		{name: "missing key", path: "data.missing", found: false},
+		{name: "index out of range", path: "items.5.id", found: false},
+		{name: "negative index", path: "items.-1", found: false},
+		{name: "non-numeric index", path: "items.first", found: false},
+		{name: "descend into scalar", path: "name.first", found: false},
+		{name: "null value", path: "empty", found: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, found := lookupJSONPath(value, tc.path)
+			if found != tc.found {
+				t.Fatalf("found = %v, want %v (value %#v)", found, tc.found, got)
+			}
+			if found && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLookupResponsePath(t *testing.T) {
+	cases := []struct {
+		name  string
+		body  string
+		path  string
+		want  interface{}
+		found bool
+	}{
+		{name: "top level", body: `{"id": "x1"}`, path: "id", want: "x1", found: true},
+		{name: "envelope data fallback", body: `{"success": true, "data": {"id": "x2"}}`, path: "id", want: "x2", found: true},
+		{name: "top level wins over envelope", body: `{"id": "outer", "data": {"id": "inner"}}`, path: "id", want: "outer", found: true},
+		{name: "not json", body: `not json`, path: "id", found: false},
+		{name: "missing everywhere", body: `{"data": {"name": "x"}}`, path: "id", found: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, found := lookupResponsePath([]byte(tc.body), tc.path)
+			if found != tc.found {
+				t.Fatalf("found = %v, want %v (value %#v)", found, tc.found, got)
+			}
+			if found && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// NormalizedJSONType is a string type whose values compare equal when they
+// hold the same JSON document, regardless of key order or whitespace.
+type NormalizedJSONType struct {
+	basetypes.StringType
+}
+
+func (t NormalizedJSONType) String() string {
+	return "NormalizedJSONType"
+}
+
+func (t NormalizedJSONType) ValueType(ctx context.Context) attr.Value {
+	return NormalizedJSONValue{}
+}
+
+func (t NormalizedJSONType) Equal(o attr.Type) bool {
+	other, ok := o.(NormalizedJSONType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t NormalizedJSONType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return NormalizedJSONValue{StringValue: in}, nil
+}
+
+func (t NormalizedJSONType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	stringValuable, diags := t.ValueFromString(ctx, stringValue)
+	if diags.HasError() {
+		return nil, fmt.Errorf("unexpected error converting StringValue to StringValuable: %v", diags)
+	}
+
+	return stringValuable, nil
+}
+
+// NormalizedJSONValue is a value of NormalizedJSONType.
+type NormalizedJSONValue struct {
+	basetypes.StringValue
+}
+
+func NewNormalizedJSONValue(value string) NormalizedJSONValue {
+	return NormalizedJSONValue{StringValue: basetypes.NewStringValue(value)}
+}
+
+func NewNormalizedJSONNull() NormalizedJSONValue {
+	return NormalizedJSONValue{StringValue: basetypes.NewStringNull()}
+}
+
+func (v NormalizedJSONValue) Type(ctx context.Context) attr.Type {
+	return NormalizedJSONType{}
+}
+
+func (v NormalizedJSONValue) Equal(o attr.Value) bool {
+	other, ok := o.(NormalizedJSONValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+// StringSemanticEquals treats two values as equal when both decode to the same
+// JSON document. Values that are not valid JSON fall back to exact comparison.
+func (v NormalizedJSONValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(NormalizedJSONValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T but got value type %T", v, newValuable),
+		)
+		return false, diags
+	}
+
+	return jsonSemanticallyEqual(v.ValueString(), newValue.ValueString()), diags
+}
+
+func jsonSemanticallyEqual(a string, b string) bool {
+	if a == b {
+		return true
+	}
+
+	decodedA, err := decodeJSON([]byte(a))
+	if err != nil {
+		return false
+	}
+	decodedB, err := decodeJSON([]byte(b))
+	if err != nil {
+		return false
+	}
+
+	return jsonValuesEqual(decodedA, decodedB)
+}
+
+// jsonValuesEqual compares values produced by decodeJSON. Numbers are compared
+// by value at full precision, so 1.0 equals 1 but IDs beyond 2^53 that differ
+// in their last digits do not compare equal.
+func jsonValuesEqual(a interface{}, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, value := range a {
+			other, ok := b[key]
+			if !ok || !jsonValuesEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonValuesEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		return jsonNumbersEqual(a, b)
+	default:
+		return a == b
+	}
+}
+
+func jsonNumbersEqual(a json.Number, b json.Number) bool {
+	if a == b {
+		return true
+	}
+
+	x, _, errA := big.ParseFloat(a.String(), 10, jsonNumberPrecision, big.ToNearestEven)
+	y, _, errB := big.ParseFloat(b.String(), 10, jsonNumberPrecision, big.ToNearestEven)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return x.Cmp(y) == 0
+}
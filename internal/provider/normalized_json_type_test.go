@@ -0,0 +1,31 @@
+package provider
+
+import "testing"
+
+func TestJSONSemanticallyEqual(t *testing.T) {
+	cases := []struct {
+		name string
+		a    string
+		b    string
+		want bool
+	}{
+		{name: "identical", a: `{"a":1}`, b: `{"a":1}`, want: true},
+		{name: "key order and whitespace", a: `{"a":1,"b":[1,2]}`, b: "{ \"b\": [1, 2],\n  \"a\": 1 }", want: true},
+		{name: "equivalent numbers", a: `{"a":1.0}`, b: `{"a":1}`, want: true},
+		{name: "large ids differ", a: `{"id":9007199254740993}`, b: `{"id":9007199254740992}`, want: false},
+		{name: "larger ids differ", a: `{"id":12345678901234567891}`, b: `{"id":12345678901234567890}`, want: false},
+		{name: "array order matters", a: `[1,2]`, b: `[2,1]`, want: false},
+		{name: "missing key", a: `{"a":1,"b":2}`, b: `{"a":1}`, want: false},
+		{name: "null vs missing", a: `{"a":null}`, b: `{}`, want: false},
+		{name: "number vs string", a: `{"a":1}`, b: `{"a":"1"}`, want: false},
+		{name: "invalid json", a: `{"a":1}`, b: `{"a":`, want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := jsonSemanticallyEqual(tc.a, tc.b); got != tc.want {
+				t.Errorf("jsonSemanticallyEqual(%s, %s) = %v, want %v", tc.a, tc.b, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"terraform-provider-customapi/go-customapi/client"
+)
+
+const (
+	updateStrategyPut        = "put"
+	updateStrategyMergePatch = "merge_patch"
+	updateStrategyJSONPatch  = "json_patch"
+)
+
+// buildPatch computes the delta between the prior and planned bodies for the
+// given update strategy and returns it with its Content-Type.
+func buildPatch(strategy string, prior []byte, planned []byte) ([]byte, string, error) {
+	original, err := decodeOptionalJSON(prior)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode prior body: %v", err)
+	}
+
+	modified, err := decodeOptionalJSON(planned)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode planned body: %v", err)
+	}
+
+	switch strategy {
+	case updateStrategyMergePatch:
+		patch, err := json.Marshal(createMergePatch(original, modified))
+		return patch, client.MergePatchContentType, err
+	case updateStrategyJSONPatch:
+		patch, err := json.Marshal(createJSONPatch(original, modified, ""))
+		return patch, client.JSONPatchContentType, err
+	default:
+		return nil, "", fmt.Errorf("unsupported update_strategy %q", strategy)
+	}
+}
+
+func decodeOptionalJSON(body []byte) (interface{}, error) {
+	if len(body) == 0 {
+		return nil, nil
+	}
+	return decodeJSON(body)
+}
+
+// createMergePatch builds an RFC 7396 merge patch. Removed keys are set to
+// null and nested objects are diffed recursively; anything else is replaced.
+func createMergePatch(original interface{}, modified interface{}) interface{} {
+	originalObject, originalIsObject := original.(map[string]interface{})
+	modifiedObject, modifiedIsObject := modified.(map[string]interface{})
+	if !originalIsObject || !modifiedIsObject {
+		return modified
+	}
+
+	patch := make(map[string]interface{})
+	for key, modifiedValue := range modifiedObject {
+		originalValue, ok := originalObject[key]
+		if ok && jsonValuesEqual(originalValue, modifiedValue) {
+			continue
+		}
+		if ok {
+			patch[key] = createMergePatch(originalValue, modifiedValue)
+		} else {
+			patch[key] = modifiedValue
+		}
+	}
+
+	for key := range originalObject {
+		if _, ok := modifiedObject[key]; !ok {
+			patch[key] = nil
+		}
+	}
+
+	return patch
+}
+
+// createJSONPatch builds an RFC 6902 JSON patch. Objects are diffed key by
+// key; arrays and scalars are replaced as a whole.
+func createJSONPatch(original interface{}, modified interface{}, pointer string) []map[string]interface{} {
+	operations := []map[string]interface{}{}
+
+	originalObject, originalIsObject := original.(map[string]interface{})
+	modifiedObject, modifiedIsObject := modified.(map[string]interface{})
+	if !originalIsObject || !modifiedIsObject {
+		if !jsonValuesEqual(original, modified) {
+			operations = append(operations, map[string]interface{}{"op": "replace", "path": pointer, "value": modified})
+		}
+		return operations
+	}
+
+	for _, key := range sortedKeys(originalObject) {
+		if _, ok := modifiedObject[key]; !ok {
+			operations = append(operations, map[string]interface{}{"op": "remove", "path": pointer + "/" + escapeJSONPointer(key)})
+		}
+	}
+
+	for _, key := range sortedKeys(modifiedObject) {
+		childPointer := pointer + "/" + escapeJSONPointer(key)
+		originalValue, ok := originalObject[key]
+		if !ok {
+			operations = append(operations, map[string]interface{}{"op": "add", "path": childPointer, "value": modifiedObject[key]})
+			continue
+		}
+		operations = append(operations, createJSONPatch(originalValue, modifiedObject[key], childPointer)...)
+	}
+
+	return operations
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func escapeJSONPointer(segment string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(segment)
+}
@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"terraform-provider-customapi/go-customapi/client"
+)
+
+func TestCreateMergePatch(t *testing.T) {
+	cases := []struct {
+		name     string
+		original string
+		modified string
+		want     string
+	}{
+		{name: "no change", original: `{"a": 1}`, modified: `{"a": 1}`, want: `{}`},
+		{name: "changed scalar", original: `{"a": 1, "b": 2}`, modified: `{"a": 1, "b": 3}`, want: `{"b": 3}`},
+		{name: "added key", original: `{"a": 1}`, modified: `{"a": 1, "b": 2}`, want: `{"b": 2}`},
+		{name: "removed key", original: `{"a": 1, "b": 2}`, modified: `{"a": 1}`, want: `{"b": null}`},
+		{name: "nested delete", original: `{"spec": {"x": 1, "y": 2}}`, modified: `{"spec": {"x": 1}}`, want: `{"spec": {"y": null}}`},
+		{name: "arrays replaced whole", original: `{"tags": ["a", "b"]}`, modified: `{"tags": ["a"]}`, want: `{"tags": ["a"]}`},
+		{name: "explicit null value", original: `{"a": 1}`, modified: `{"a": null}`, want: `{"a": null}`},
+		{name: "object replaced by scalar", original: `{"spec": {"x": 1}}`, modified: `{"spec": "none"}`, want: `{"spec": "none"}`},
+		{name: "non-object document", original: `[1]`, modified: `[2]`, want: `[2]`},
+		{name: "equivalent numbers", original: `{"a": 1.0, "b": [1e2]}`, modified: `{"a": 1, "b": [100]}`, want: `{}`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			original, _ := decodeJSON([]byte(tc.original))
+			modified, _ := decodeJSON([]byte(tc.modified))
+
+			got, err := json.Marshal(createMergePatch(original, modified))
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if !jsonSemanticallyEqual(string(got), tc.want) {
+				t.Errorf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCreateJSONPatch(t *testing.T) {
+	cases := []struct {
+		name     string
+		original string
+		modified string
+		want     string
+	}{
+		{name: "no change", original: `{"a": 1}`, modified: `{"a": 1}`, want: `[]`},
+		{name: "replace scalar", original: `{"a": 1}`, modified: `{"a": 2}`, want: `[{"op": "replace", "path": "/a", "value": 2}]`},
+		{name: "add key", original: `{}`, modified: `{"a": 1}`, want: `[{"op": "add", "path": "/a", "value": 1}]`},
+		{name: "remove key", original: `{"a": 1}`, modified: `{}`, want: `[{"op": "remove", "path": "/a"}]`},
+		{
+			name:     "nested delete",
+			original: `{"spec": {"x": 1, "y": 2}}`,
+			modified: `{"spec": {"x": 1}}`,
+			want:     `[{"op": "remove", "path": "/spec/y"}]`,
+		},
+		{
+			name:     "arrays replaced whole",
+			original: `{"tags": ["a", "b"]}`,
+			modified: `{"tags": ["b"]}`,
+			want:     `[{"op": "replace", "path": "/tags", "value": ["b"]}]`,
+		},
+		{
+			name:     "set to null",
+			original: `{"a": 1}`,
+			modified: `{"a": null}`,
+			want:     `[{"op": "replace", "path": "/a", "value": null}]`,
+		},
+		{
+			name:     "escaped keys",
+			original: `{"a/b": 1, "c~d": 1}`,
+			modified: `{"a/b": 2}`,
+			want:     `[{"op": "remove", "path": "/c~0d"}, {"op": "replace", "path": "/a~1b", "value": 2}]`,
+		},
+		{
+			name:     "removes before adds in key order",
+			original: `{"b": 1, "d": 1}`,
+			modified: `{"a": 1, "c": 1}`,
+			want:     `[{"op": "remove", "path": "/b"}, {"op": "remove", "path": "/d"}, {"op": "add", "path": "/a", "value": 1}, {"op": "add", "path": "/c", "value": 1}]`,
+		},
+		{name: "whole document", original: `[1]`, modified: `[2]`, want: `[{"op": "replace", "path": "", "value": [2]}]`},
+		{name: "equivalent numbers", original: `{"a": 1.0, "b": [1e2]}`, modified: `{"a": 1, "b": [100]}`, want: `[]`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			original, _ := decodeJSON([]byte(tc.original))
+			modified, _ := decodeJSON([]byte(tc.modified))
+
+			got, err := json.Marshal(createJSONPatch(original, modified, ""))
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if !jsonSemanticallyEqual(string(got), tc.want) {
+				t.Errorf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBuildPatch(t *testing.T) {
+	cases := []struct {
+		name        string
+		strategy    string
+		prior       string
+		planned     string
+		want        string
+		contentType string
+		wantErr     bool
+	}{
+		{
+			name:        "merge patch",
+			strategy:    updateStrategyMergePatch,
+			prior:       `{"a": 1, "id": 12345678901234567890}`,
+			planned:     `{"a": 2, "id": 12345678901234567890}`,
+			want:        `{"a": 2}`,
+			contentType: client.MergePatchContentType,
+		},
+		{
+			name:        "json patch from empty prior",
+			strategy:    updateStrategyJSONPatch,
+			prior:       ``,
+			planned:     `{"a": 1}`,
+			want:        `[{"op": "replace", "path": "", "value": {"a": 1}}]`,
+			contentType: client.JSONPatchContentType,
+		},
+		{name: "unsupported strategy", strategy: updateStrategyPut, prior: `{}`, planned: `{}`, wantErr: true},
+		{name: "invalid planned body", strategy: updateStrategyMergePatch, prior: `{}`, planned: `{`, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			patch, contentType, err := buildPatch(tc.strategy, []byte(tc.prior), []byte(tc.planned))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got patch %s", patch)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildPatch: %v", err)
+			}
+			if contentType != tc.contentType {
+				t.Errorf("content type = %q, want %q", contentType, tc.contentType)
+			}
+			if !jsonSemanticallyEqual(string(patch), tc.want) {
+				t.Errorf("got %s, want %s", patch, tc.want)
+			}
+		})
+	}
+}
+
+func TestPatchStrategyPathValidator(t *testing.T) {
+	s := testResourceSchema(t)
+
+	cases := []struct {
+		name    string
+		values  map[string]tftypes.Value
+		wantErr bool
+	}{
+		{name: "default strategy", values: map[string]tftypes.Value{}},
+		{name: "put without paths", values: map[string]tftypes.Value{
+			"update_strategy": tftypes.NewValue(tftypes.String, updateStrategyPut),
+		}},
+		{name: "merge patch without paths", values: map[string]tftypes.Value{
+			"update_strategy": tftypes.NewValue(tftypes.String, updateStrategyMergePatch),
+		}, wantErr: true},
+		{name: "json patch with read_path", values: map[string]tftypes.Value{
+			"update_strategy": tftypes.NewValue(tftypes.String, updateStrategyJSONPatch),
+			"read_path":       tftypes.NewValue(tftypes.String, "/api/users/{id}"),
+		}},
+		{name: "merge patch with update_path", values: map[string]tftypes.Value{
+			"update_strategy": tftypes.NewValue(tftypes.String, updateStrategyMergePatch),
+			"update_path":     tftypes.NewValue(tftypes.String, "/api/users/{id}"),
+		}},
+		{name: "merge patch with unknown path", values: map[string]tftypes.Value{
+			"update_strategy": tftypes.NewValue(tftypes.String, updateStrategyMergePatch),
+			"update_path":     tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := resource.ValidateConfigRequest{
+				Config: tfsdk.Config{Schema: s, Raw: testObjectValue(t, s, tc.values)},
+			}
+			resp := &resource.ValidateConfigResponse{}
+
+			patchStrategyPathValidator{}.ValidateResource(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tc.wantErr {
+				t.Errorf("HasError = %v, want %v: %v", resp.Diagnostics.HasError(), tc.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
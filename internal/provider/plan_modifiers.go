@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+)
+
+// requiresReplaceIfPreviouslySet replaces the object when an identity
+// attribute changes. Values that were unset in state, such as attributes that
+// import could not recover, can be filled in without a replacement.
+func requiresReplaceIfPreviouslySet() planmodifier.String {
+	description := "Changing this value forces the object to be replaced, unless it was previously unset."
+
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			resp.RequiresReplace = !req.StateValue.IsNull()
+		},
+		description,
+		description,
+	)
+}
+
+// changedBodyPaths returns the paths from replaceOnChange whose value differs
+// between the prior and planned body.
+func changedBodyPaths(prior []byte, planned []byte, replaceOnChange []string) []string {
+	priorValue, err := decodeOptionalJSON(prior)
+	if err != nil {
+		return nil
+	}
+
+	plannedValue, err := decodeOptionalJSON(planned)
+	if err != nil {
+		return nil
+	}
+
+	var changed []string
+	for _, fieldPath := range replaceOnChange {
+		before, _ := lookupJSONPath(priorValue, fieldPath)
+		after, _ := lookupJSONPath(plannedValue, fieldPath)
+		if !jsonValuesEqual(before, after) {
+			changed = append(changed, fieldPath)
+		}
+	}
+	return changed
+}
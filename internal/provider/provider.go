@@ -3,13 +3,25 @@ package provider
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/providervalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"net/url"
+	"sort"
+	"strings"
 	"terraform-provider-customapi/go-customapi/client"
+	"time"
 )
 
 type CustomAPIProvider struct {
@@ -17,12 +29,33 @@ type CustomAPIProvider struct {
 }
 
 type CustomAPIProviderModel struct {
-	Username    types.String `tfsdk:"username"`
-	Password    types.String `tfsdk:"password"`
-	AuthToken   types.String `tfsdk:"auth_token"`
-	Environment types.String `tfsdk:"environment"`
-	BaseURL     types.String `tfsdk:"base_url"`
-	OrgID       types.String `tfsdk:"org_id"`
+	Username               types.String `tfsdk:"username"`
+	Password               types.String `tfsdk:"password"`
+	AuthToken              types.String `tfsdk:"auth_token"`
+	AuthMethod             types.String `tfsdk:"auth_method"`
+	ClientID               types.String `tfsdk:"client_id"`
+	ClientSecret           types.String `tfsdk:"client_secret"`
+	Audience               types.String `tfsdk:"audience"`
+	Scopes                 types.List   `tfsdk:"scopes"`
+	OfflineAccess          types.Bool   `tfsdk:"offline_access"`
+	Environment            types.String `tfsdk:"environment"`
+	Environments           types.Map    `tfsdk:"environments"`
+	BaseURL                types.String `tfsdk:"base_url"`
+	AuthURL                types.String `tfsdk:"auth_url"`
+	IssuerURL              types.String `tfsdk:"issuer_url"`
+	OrgID                  types.String `tfsdk:"org_id"`
+	ExpectedStatusCodes    types.List   `tfsdk:"expected_status_codes"`
+	Retry                  types.Object `tfsdk:"retry"`
+	EnvFile                types.String `tfsdk:"env_file"`
+	TokenExpirySkewSeconds types.Int64  `tfsdk:"token_expiry_skew_seconds"`
+}
+
+type CustomAPIProviderRetryModel struct {
+	MaxAttempts          types.Int64 `tfsdk:"max_attempts"`
+	MinBackoffSeconds    types.Int64 `tfsdk:"min_backoff_seconds"`
+	MaxBackoffSeconds    types.Int64 `tfsdk:"max_backoff_seconds"`
+	RetryNonIdempotent   types.Bool  `tfsdk:"retry_non_idempotent"`
+	RetryableStatusCodes types.List  `tfsdk:"retryable_status_codes"`
 }
 
 func (p *CustomAPIProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -47,22 +80,190 @@ func (p *CustomAPIProvider) Schema(ctx context.Context, req provider.SchemaReque
 				Sensitive:   true,
 				Description: "Auth token for direct authentication",
 			},
+			"auth_method": schema.StringAttribute{
+				Optional:    true,
+				Description: "Authentication method: token uses auth_token, password uses the OAuth2 password grant with username and password, client_credentials uses the OAuth2 client credentials grant with client_id and client_secret. Inferred from the credentials provided when unset",
+				Validators: []validator.String{
+					stringvalidator.OneOf(client.AuthMethodToken, client.AuthMethodPassword, client.AuthMethodClientCredentials),
+				},
+			},
+			"client_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "OAuth2 client ID (defaults to CUSTOMAPI_CLIENT_ID)",
+			},
+			"client_secret": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "OAuth2 client secret for the client_credentials method (defaults to CUSTOMAPI_CLIENT_SECRET)",
+			},
+			"audience": schema.StringAttribute{
+				Optional:    true,
+				Description: "OAuth2 audience requested for the access token (defaults to CUSTOMAPI_AUDIENCE)",
+			},
+			"scopes": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "OAuth2 scopes requested for the access token (defaults to openid, profile and email for the password method)",
+			},
+			"offline_access": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Request the offline_access scope and renew expired tokens with the returned refresh token instead of re-sending credentials (defaults to false)",
+			},
 			"environment": schema.StringAttribute{
 				Optional:    true,
-				Description: "Environment (qa, staging, prod)",
+				Description: "Environment (qa, staging, prod) or the name of a profile in environments (defaults to CUSTOMAPI_PROFILE, then CUSTOMAPI_ENVIRONMENT)",
+			},
+			"environments": environmentsAttribute(),
+			"env_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a dotenv file with CUSTOMAPI_ variables, used for values set neither in the provider block nor in the environment. No .env file is read unless this is set",
 			},
 			"base_url": schema.StringAttribute{
 				Optional:    true,
 				Description: "Base URL for the API",
 			},
+			"auth_url": schema.StringAttribute{
+				Optional:    true,
+				Description: "Authorization server URL; tokens are requested from <auth_url>/oauth/token (defaults to CUSTOMAPI_AUTH_URL, then the tenant of environment)",
+			},
+			"issuer_url": schema.StringAttribute{
+				Optional:    true,
+				Description: "OIDC issuer URL; the token endpoint is discovered from its /.well-known/openid-configuration instead of being derived from environment",
+			},
 			"org_id": schema.StringAttribute{
 				Optional:    true,
-				Description: "Organization ID",
+				Description: "Default organization ID sent in the current-organization header of every request (defaults to CUSTOMAPI_ORG_ID)",
+			},
+			"expected_status_codes": schema.ListAttribute{
+				ElementType: types.Int64Type,
+				Optional:    true,
+				Description: "Default status codes treated as success by resources and data sources (defaults to any 2xx)",
+				Validators: []validator.List{
+					statusCodesValidator(),
+				},
+			},
+			"token_expiry_skew_seconds": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Refresh the cached access token this many seconds before it expires (defaults to 30, capped at half the token lifetime)",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Retry settings for transient failures. Only idempotent methods are retried unless retry_non_idempotent is true",
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Total number of attempts including the first one, 1 disables retries (defaults to 3)",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"min_backoff_seconds": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Delay before the first retry, doubled on every attempt (defaults to 1)",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"max_backoff_seconds": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Upper bound for the delay between attempts, including Retry-After (defaults to 30)",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"retry_non_idempotent": schema.BoolAttribute{
+						Optional:    true,
+						Description: "Also retry POST and PATCH requests, which may apply a change twice (defaults to false)",
+					},
+					"retryable_status_codes": schema.ListAttribute{
+						ElementType: types.Int64Type,
+						Optional:    true,
+						Description: "Status codes that are retried (defaults to 429, 502, 503 and 504)",
+						Validators: []validator.List{
+							statusCodesValidator(),
+						},
+					},
+				},
 			},
 		},
 	}
 }
 
+func (p *CustomAPIProvider) ConfigValidators(ctx context.Context) []provider.ConfigValidator {
+	return []provider.ConfigValidator{
+		providervalidator.Conflicting(
+			path.MatchRoot("auth_token"),
+			path.MatchRoot("username"),
+		),
+		providervalidator.Conflicting(
+			path.MatchRoot("auth_token"),
+			path.MatchRoot("password"),
+		),
+		providervalidator.Conflicting(
+			path.MatchRoot("auth_token"),
+			path.MatchRoot("client_secret"),
+		),
+	}
+}
+
+func (p *CustomAPIProvider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var config CustomAPIProviderModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Profile names can only be checked once the environments map is known.
+	if !config.Environments.IsUnknown() {
+		environments := environmentProfiles(ctx, config.Environments, &resp.Diagnostics)
+
+		if isKnownString(config.Environment) && !isKnownEnvironment(config.Environment.ValueString(), environments) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("environment"),
+				"Unknown Environment",
+				fmt.Sprintf("environment must be one of %s, got: %q", strings.Join(environmentNames(environments), ", "), config.Environment.ValueString()),
+			)
+		}
+
+		validateEnvironmentProfiles(environments, &resp.Diagnostics)
+	}
+
+	if isKnownString(config.BaseURL) {
+		if err := validateBaseURL(config.BaseURL.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("base_url"),
+				"Invalid Base URL",
+				fmt.Sprintf("base_url %v", err),
+			)
+		}
+	}
+
+	if isKnownString(config.AuthURL) {
+		if err := validateBaseURL(config.AuthURL.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("auth_url"),
+				"Invalid Auth URL",
+				fmt.Sprintf("auth_url %v", err),
+			)
+		}
+	}
+
+	if isKnownString(config.IssuerURL) {
+		if err := validateBaseURL(config.IssuerURL.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("issuer_url"),
+				"Invalid Issuer URL",
+				fmt.Sprintf("issuer_url %v", err),
+			)
+		}
+	}
+}
+
 func (p *CustomAPIProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var config CustomAPIProviderModel
 
@@ -72,68 +273,204 @@ func (p *CustomAPIProvider) Configure(ctx context.Context, req provider.Configur
 		return
 	}
 
-	// Load environment configuration
+	unknown := unknownAttributes(ctx, config)
+	if len(unknown) > 0 {
+		if req.ClientCapabilities.DeferralAllowed {
+			tflog.Debug(ctx, "Provider configuration is not yet known, deferring", map[string]interface{}{
+				"attributes": unknown,
+			})
+			resp.Deferred = &provider.Deferred{
+				Reason: provider.DeferredReasonProviderConfigUnknown,
+			}
+			return
+		}
+
+		for _, name := range unknown {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name),
+				"Unknown Provider Configuration Value",
+				fmt.Sprintf("The provider cannot create the API client because %s depends on a value that is not known until apply. Set it statically, use the matching CUSTOMAPI_ environment variable, or apply the dependency first with -target.", name),
+			)
+		}
+		return
+	}
+
+	// Configuration is resolved once here: the provider block, then
+	// environment variables, then the optional env_file.
 	envConfig, err := client.LoadConfig()
 	if err != nil {
-		resp.Diagnostics.AddWarning(
+		resp.Diagnostics.AddError(
 			"Failed to load environment config",
-			fmt.Sprintf("Using defaults: %v", err),
+			err.Error(),
 		)
+		return
 	}
 
-	// Use provider config values if provided, otherwise fall back to environment
-	username := config.Username.ValueString()
-	if username == "" {
-		username = envConfig.Username
+	envFile := config.EnvFile.ValueString()
+	var fileConfig *client.Config
+	if envFile != "" {
+		fileConfig, err = client.LoadConfigFile(envFile)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("env_file"),
+				"Failed to Read env_file",
+				err.Error(),
+			)
+			return
+		}
 	}
 
-	password := config.Password.ValueString()
-	if password == "" {
-		password = envConfig.Password
+	environments := environmentProfiles(ctx, config.Environments, &resp.Diagnostics)
+	retry := retryModel(ctx, config.Retry, &resp.Diagnostics)
+
+	var scopes []string
+	if !config.Scopes.IsNull() {
+		resp.Diagnostics.Append(config.Scopes.ElementsAs(ctx, &scopes, false)...)
 	}
 
-	authToken := config.AuthToken.ValueString()
-	if authToken == "" {
-		authToken = envConfig.AuthToken
+	var expectedStatusCodes []types.Int64
+	if !config.ExpectedStatusCodes.IsNull() {
+		resp.Diagnostics.Append(config.ExpectedStatusCodes.ElementsAs(ctx, &expectedStatusCodes, false)...)
 	}
 
-	environment := config.Environment.ValueString()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resolver := newConfigResolver(envConfig, fileConfig, envFile)
+
+	// Unless auth_method is set, credentials configured in the provider block
+	// take precedence as a whole: if the block sets any credential, none is
+	// read from the environment, so environment credentials for another
+	// method never combine with the configured ones.
+	authMethod := config.AuthMethod.ValueString()
+	username := config.Username.ValueString()
+	password := config.Password.ValueString()
+	authToken := config.AuthToken.ValueString()
+	clientSecret := config.ClientSecret.ValueString()
+
+	configuresCredentials := authToken != "" || username != "" || password != "" || clientSecret != ""
+	fromEnvironment := authMethod != "" || !configuresCredentials
+
+	authToken = resolver.resolve("auth_token", environmentVariable(fromEnvironment, "CUSTOMAPI_AUTH_TOKEN"), func(c *client.Config) string { return c.AuthToken }, providerValue(config.AuthToken))
+	clientSecret = resolver.resolve("client_secret", environmentVariable(fromEnvironment, "CUSTOMAPI_CLIENT_SECRET"), func(c *client.Config) string { return c.ClientSecret }, providerValue(config.ClientSecret))
+
+	username = resolver.resolve("username", environmentVariable(fromEnvironment, "CUSTOMAPI_USERNAME"), func(c *client.Config) string { return c.Username }, providerValue(config.Username))
+	password = resolver.resolve("password", environmentVariable(fromEnvironment, "CUSTOMAPI_PASSWORD"), func(c *client.Config) string { return c.Password }, providerValue(config.Password))
+
+	// The selected profile fills in whatever the provider block leaves unset,
+	// ahead of the individual environment variables.
+	environment := resolver.resolve("environment", "CUSTOMAPI_PROFILE", func(c *client.Config) string { return c.Profile }, providerValue(config.Environment))
 	if environment == "" {
-		environment = envConfig.Environment
+		environment = resolver.resolve("environment", "CUSTOMAPI_ENVIRONMENT", func(c *client.Config) string { return c.Environment })
 	}
+	profile := environments[environment]
+
+	clientID := resolver.resolve("client_id", "CUSTOMAPI_CLIENT_ID", func(c *client.Config) string { return c.ClientID }, providerValue(config.ClientID), profileValue(environment, profile.ClientID))
+	audience := resolver.resolve("audience", "CUSTOMAPI_AUDIENCE", func(c *client.Config) string { return c.Audience }, providerValue(config.Audience), profileValue(environment, profile.Audience))
+	baseURL := resolver.resolve("base_url", "CUSTOMAPI_BASE_URL", func(c *client.Config) string { return c.BaseURL }, providerValue(config.BaseURL), profileValue(environment, profile.BaseURL))
+	authURL := resolver.resolve("auth_url", "CUSTOMAPI_AUTH_URL", func(c *client.Config) string { return c.AuthURL }, providerValue(config.AuthURL), profileValue(environment, profile.AuthURL))
+	issuerURL := resolver.resolve("issuer_url", "CUSTOMAPI_ISSUER_URL", func(c *client.Config) string { return c.IssuerURL }, providerValue(config.IssuerURL), profileValue(environment, profile.IssuerURL))
+	orgID := resolver.resolve("org_id", "CUSTOMAPI_ORG_ID", func(c *client.Config) string { return c.DefaultOrgID }, providerValue(config.OrgID), profileValue(environment, profile.OrgID))
+
+	tflog.Debug(ctx, "Resolved provider configuration sources", resolver.sources)
 
-	baseURL := config.BaseURL.ValueString()
-	if baseURL == "" {
-		baseURL = envConfig.BaseURL
+	authMethod, err = resolveAuthMethod(authMethod, authToken, username, password, clientID, clientSecret)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Authentication",
+			err.Error(),
+		)
+		return
 	}
 
-	orgID := config.OrgID.ValueString()
-	if orgID == "" {
-		orgID = envConfig.DefaultOrgID
+	if environment != "" && !isKnownEnvironment(environment, environments) {
+		resp.Diagnostics.AddError(
+			"Unknown Environment",
+			fmt.Sprintf("CUSTOMAPI_PROFILE or CUSTOMAPI_ENVIRONMENT must be one of %s, got: %q", strings.Join(environmentNames(environments), ", "), environment),
+		)
+		return
 	}
 
-	if authToken == "" && (username == "" || password == "") {
+	// Only the built-in environments have a tenant to fall back to; checked
+	// after resolution, since auth_url or issuer_url may come from anywhere.
+	if authURL == "" && issuerURL == "" && environment != "" && !containsName(knownEnvironments, environment) {
 		resp.Diagnostics.AddError(
-			"Missing Authentication",
-			"Either auth_token or both username and password must be provided in provider config or environment variables",
+			"Missing Auth URL",
+			fmt.Sprintf("environment %q is not one of %s, so auth_url or issuer_url must be set in its profile, the provider block, CUSTOMAPI_AUTH_URL or CUSTOMAPI_ISSUER_URL", environment, strings.Join(knownEnvironments, ", ")),
 		)
 		return
 	}
 
+	if authURL != "" {
+		if err := validateBaseURL(authURL); err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Auth URL",
+				fmt.Sprintf("CUSTOMAPI_AUTH_URL %v", err),
+			)
+			return
+		}
+	}
+
+	if baseURL != "" {
+		if err := validateBaseURL(baseURL); err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Base URL",
+				fmt.Sprintf("CUSTOMAPI_BASE_URL %v", err),
+			)
+			return
+		}
+	}
+
+	if issuerURL != "" {
+		if err := validateBaseURL(issuerURL); err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Issuer URL",
+				fmt.Sprintf("CUSTOMAPI_ISSUER_URL %v", err),
+			)
+			return
+		}
+	}
+
 	authConfig := &client.AuthConfig{
-		Username:    username,
-		Password:    password,
-		AuthToken:   authToken,
-		Environment: environment,
-		BaseURL:     envConfig.AuthURL,
+		AuthMethod:    authMethod,
+		Environment:   environment,
+		BaseURL:       authURL,
+		IssuerURL:     issuerURL,
+		ClientID:      clientID,
+		Audience:      audience,
+		ExpirySkew:    client.DefaultTokenExpirySkew,
+		OfflineAccess: config.OfflineAccess.ValueBool(),
+		Scopes:        scopes,
+	}
+
+	// Only hand the credentials of the selected method to the client.
+	switch authMethod {
+	case client.AuthMethodToken:
+		authConfig.AuthToken = authToken
+	case client.AuthMethodPassword:
+		authConfig.Username = username
+		authConfig.Password = password
+	case client.AuthMethodClientCredentials:
+		authConfig.ClientSecret = clientSecret
+	}
+
+	if !config.TokenExpirySkewSeconds.IsNull() {
+		authConfig.ExpirySkew = time.Duration(config.TokenExpirySkewSeconds.ValueInt64()) * time.Second
 	}
 
 	apiClient := client.NewCustomAPIClient(authConfig, baseURL)
+	apiClient.SetExpectedStatusCodes(int64sToInts(expectedStatusCodes))
+	apiClient.SetRetryConfig(retryConfig(ctx, retry, &resp.Diagnostics))
+	apiClient.SetDefaultOrgID(orgID)
 
 	ctx = tflog.SetField(ctx, "customapi_provider", "configured")
 	tflog.Info(ctx, "CustomAPI provider configured", map[string]interface{}{
+		"auth_method": authMethod,
 		"environment": environment,
 		"base_url":    baseURL,
+		"auth_url":    authURL,
+		"issuer_url":  issuerURL,
 		"org_id":      orgID,
 	})
 
@@ -141,6 +478,164 @@ func (p *CustomAPIProvider) Configure(ctx context.Context, req provider.Configur
 	resp.DataSourceData = apiClient
 }
 
+// retryModel decodes the retry block, returning nil when it is not set.
+func retryModel(ctx context.Context, value types.Object, diags *diag.Diagnostics) *CustomAPIProviderRetryModel {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+
+	var model CustomAPIProviderRetryModel
+	diags.Append(value.As(ctx, &model, basetypes.ObjectAsOptions{})...)
+	return &model
+}
+
+func retryConfig(ctx context.Context, model *CustomAPIProviderRetryModel, diags *diag.Diagnostics) client.RetryConfig {
+	retry := client.DefaultRetryConfig()
+	if model == nil {
+		return retry
+	}
+
+	if !model.MaxAttempts.IsNull() {
+		retry.MaxAttempts = int(model.MaxAttempts.ValueInt64())
+	}
+	if !model.MinBackoffSeconds.IsNull() {
+		retry.MinBackoff = time.Duration(model.MinBackoffSeconds.ValueInt64()) * time.Second
+	}
+	if !model.MaxBackoffSeconds.IsNull() {
+		retry.MaxBackoff = time.Duration(model.MaxBackoffSeconds.ValueInt64()) * time.Second
+	}
+	if !model.RetryNonIdempotent.IsNull() {
+		retry.RetryNonIdempotent = model.RetryNonIdempotent.ValueBool()
+	}
+	if !model.RetryableStatusCodes.IsNull() {
+		var codes []types.Int64
+		diags.Append(model.RetryableStatusCodes.ElementsAs(ctx, &codes, false)...)
+		retry.RetryableStatusCodes = int64sToInts(codes)
+	}
+
+	return retry
+}
+
+// resolveAuthMethod validates an explicit auth_method, or infers the method
+// from the credentials that are present and rejects ambiguous combinations.
+func resolveAuthMethod(method, authToken, username, password, clientID, clientSecret string) (string, error) {
+	if method == "" {
+		var present []string
+		if authToken != "" {
+			present = append(present, client.AuthMethodToken)
+		}
+		if username != "" || password != "" {
+			present = append(present, client.AuthMethodPassword)
+		}
+		if clientSecret != "" {
+			present = append(present, client.AuthMethodClientCredentials)
+		}
+
+		switch len(present) {
+		case 0:
+			return "", fmt.Errorf("Either auth_token, both username and password, or client_id and client_secret must be provided in provider config or environment variables")
+		case 1:
+			method = present[0]
+		default:
+			return "", fmt.Errorf("Credentials for several authentication methods were found (%s) in the provider config or environment variables (CUSTOMAPI_AUTH_TOKEN, CUSTOMAPI_USERNAME, CUSTOMAPI_PASSWORD, CUSTOMAPI_CLIENT_SECRET). Set only one, or select one with auth_method", strings.Join(present, ", "))
+		}
+	}
+
+	switch method {
+	case client.AuthMethodToken:
+		if authToken == "" {
+			return "", fmt.Errorf("auth_method %q requires auth_token or CUSTOMAPI_AUTH_TOKEN", method)
+		}
+	case client.AuthMethodPassword:
+		if username == "" || password == "" {
+			return "", fmt.Errorf("auth_method %q requires both username and password, or CUSTOMAPI_USERNAME and CUSTOMAPI_PASSWORD", method)
+		}
+	case client.AuthMethodClientCredentials:
+		if clientID == "" || clientSecret == "" {
+			return "", fmt.Errorf("auth_method %q requires client_id and client_secret, or CUSTOMAPI_CLIENT_ID and CUSTOMAPI_CLIENT_SECRET", method)
+		}
+	}
+
+	return method, nil
+}
+
+// validateBaseURL requires an absolute http(s) URL without query or fragment.
+func validateBaseURL(baseURL string) error {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("is not a valid URL: %v", err)
+	}
+
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("must be an absolute http or https URL, got: %q", baseURL)
+	}
+
+	if parsed.RawQuery != "" || parsed.Fragment != "" {
+		return fmt.Errorf("must not contain a query string or fragment, got: %q", baseURL)
+	}
+
+	return nil
+}
+
+// containsUnknown reports whether value is unknown or holds an unknown value
+// at any depth.
+func containsUnknown(ctx context.Context, value attr.Value) bool {
+	terraformValue, err := value.ToTerraformValue(ctx)
+	if err != nil {
+		return false
+	}
+	return !terraformValue.IsFullyKnown()
+}
+
+func isKnownString(value types.String) bool {
+	return !value.IsNull() && !value.IsUnknown() && value.ValueString() != ""
+}
+
+// unknownAttributes lists the provider attributes whose values are not yet
+// known, including collections and nested blocks containing unknown values.
+func unknownAttributes(ctx context.Context, config CustomAPIProviderModel) []string {
+	values := map[string]types.String{
+		"username":      config.Username,
+		"password":      config.Password,
+		"auth_token":    config.AuthToken,
+		"auth_method":   config.AuthMethod,
+		"client_id":     config.ClientID,
+		"audience":      config.Audience,
+		"client_secret": config.ClientSecret,
+		"environment":   config.Environment,
+		"base_url":      config.BaseURL,
+		"auth_url":      config.AuthURL,
+		"issuer_url":    config.IssuerURL,
+		"org_id":        config.OrgID,
+		"env_file":      config.EnvFile,
+	}
+
+	var unknown []string
+	for name, value := range values {
+		if value.IsUnknown() {
+			unknown = append(unknown, name)
+		}
+	}
+
+	// Collections and nested blocks are also unknown when any element is.
+	otherValues := map[string]attr.Value{
+		"scopes":                    config.Scopes,
+		"expected_status_codes":     config.ExpectedStatusCodes,
+		"environments":              config.Environments,
+		"retry":                     config.Retry,
+		"token_expiry_skew_seconds": config.TokenExpirySkewSeconds,
+		"offline_access":            config.OfflineAccess,
+	}
+
+	for name, value := range otherValues {
+		if containsUnknown(ctx, value) {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
 func (p *CustomAPIProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewCustomAPIResource,
@@ -0,0 +1,231 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func testProviderConfig(t *testing.T, values map[string]interface{}) tfsdk.Config {
+	t.Helper()
+
+	schemaResp := &provider.SchemaResponse{}
+	(&CustomAPIProvider{}).Schema(context.Background(), provider.SchemaRequest{}, schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("schema: %v", schemaResp.Diagnostics)
+	}
+
+	objectType := schemaResp.Schema.Type().TerraformType(context.Background()).(tftypes.Object)
+	attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+		if value, ok := values[name]; ok {
+			if value, ok := value.(tftypes.Value); ok {
+				attributes[name] = value
+				continue
+			}
+			attributes[name] = tftypes.NewValue(attributeType, value)
+		}
+	}
+
+	return tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, attributes)}
+}
+
+func TestProviderUnknownValues(t *testing.T) {
+	for _, name := range []string{"scopes", "expected_status_codes", "environments", "retry", "token_expiry_skew_seconds", "offline_access"} {
+		t.Run(name, func(t *testing.T) {
+			config := testProviderConfig(t, map[string]interface{}{
+				name:          tftypes.UnknownValue,
+				"environment": "custom",
+			})
+			p := &CustomAPIProvider{}
+
+			validateResp := &provider.ValidateConfigResponse{}
+			p.ValidateConfig(context.Background(), provider.ValidateConfigRequest{Config: config}, validateResp)
+			if name != "environments" {
+				// A profile name outside the known environments is still rejected.
+				if !validateResp.Diagnostics.HasError() {
+					t.Errorf("expected the unknown environment name to be rejected")
+				}
+			} else if validateResp.Diagnostics.HasError() {
+				t.Errorf("ValidateConfig: %v", validateResp.Diagnostics)
+			}
+
+			configureResp := &provider.ConfigureResponse{}
+			p.Configure(context.Background(), provider.ConfigureRequest{
+				Config:             config,
+				ClientCapabilities: provider.ConfigureProviderClientCapabilities{DeferralAllowed: true},
+			}, configureResp)
+			if configureResp.Diagnostics.HasError() {
+				t.Fatalf("Configure: %v", configureResp.Diagnostics)
+			}
+			if configureResp.Deferred == nil {
+				t.Errorf("expected Configure to defer while %s is unknown", name)
+			}
+		})
+	}
+}
+
+func TestConfigureCredentialsResolvedAsWhole(t *testing.T) {
+	cases := []struct {
+		name   string
+		values map[string]interface{}
+		env    map[string]string
+	}{
+		{
+			name:   "client secret ignores environment password",
+			values: map[string]interface{}{"client_id": "id", "client_secret": "secret"},
+			env:    map[string]string{"CUSTOMAPI_USERNAME": "user", "CUSTOMAPI_PASSWORD": "pass"},
+		},
+		{
+			name:   "auth token ignores environment password",
+			values: map[string]interface{}{"auth_token": "token"},
+			env:    map[string]string{"CUSTOMAPI_USERNAME": "user", "CUSTOMAPI_PASSWORD": "pass"},
+		},
+		{
+			name:   "username ignores environment auth token",
+			values: map[string]interface{}{"username": "user", "password": "pass"},
+			env:    map[string]string{"CUSTOMAPI_AUTH_TOKEN": "token", "CUSTOMAPI_CLIENT_SECRET": "secret"},
+		},
+		{
+			name: "environment credentials when the block sets none",
+			env:  map[string]string{"CUSTOMAPI_USERNAME": "user", "CUSTOMAPI_PASSWORD": "pass"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, name := range []string{"CUSTOMAPI_USERNAME", "CUSTOMAPI_PASSWORD", "CUSTOMAPI_AUTH_TOKEN", "CUSTOMAPI_CLIENT_SECRET"} {
+				t.Setenv(name, tc.env[name])
+			}
+
+			values := map[string]interface{}{"base_url": "https://api.example.com"}
+			for name, value := range tc.values {
+				values[name] = value
+			}
+
+			resp := &provider.ConfigureResponse{}
+			(&CustomAPIProvider{}).Configure(context.Background(), provider.ConfigureRequest{Config: testProviderConfig(t, values)}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Configure: %v", resp.Diagnostics)
+			}
+		})
+	}
+}
+
+// testEnvironments builds an environments map value from profile attributes,
+// leaving unset attributes null.
+func testEnvironments(t *testing.T, profiles map[string]map[string]string) tftypes.Value {
+	t.Helper()
+
+	mapType := environmentsAttribute().GetType().TerraformType(context.Background()).(tftypes.Map)
+	profileType := mapType.ElementType.(tftypes.Object)
+
+	elements := make(map[string]tftypes.Value, len(profiles))
+	for name, profile := range profiles {
+		attributes := make(map[string]tftypes.Value, len(profileType.AttributeTypes))
+		for attribute := range profileType.AttributeTypes {
+			attributes[attribute] = tftypes.NewValue(tftypes.String, nil)
+			if value, ok := profile[attribute]; ok {
+				attributes[attribute] = tftypes.NewValue(tftypes.String, value)
+			}
+		}
+		elements[name] = tftypes.NewValue(profileType, attributes)
+	}
+	return tftypes.NewValue(mapType, elements)
+}
+
+func TestValidateEnvironmentProfiles(t *testing.T) {
+	cases := []struct {
+		name         string
+		values       map[string]interface{}
+		profiles     map[string]map[string]string
+		env          map[string]string
+		wantValidate bool
+		wantErr      bool
+	}{
+		{
+			name:     "built-in environment",
+			values:   map[string]interface{}{"environment": "staging"},
+			profiles: map[string]map[string]string{"staging": {"base_url": "https://api.staging.example.com"}},
+		},
+		{
+			name:     "profile auth_url",
+			values:   map[string]interface{}{"environment": "dev"},
+			profiles: map[string]map[string]string{"dev": {"auth_url": "https://auth.dev.example.com"}},
+		},
+		{
+			name:     "profile issuer_url",
+			values:   map[string]interface{}{"environment": "dev"},
+			profiles: map[string]map[string]string{"dev": {"issuer_url": "https://login.example.com/realms/dev"}},
+		},
+		{
+			name:     "provider issuer_url",
+			values:   map[string]interface{}{"environment": "dev", "issuer_url": "https://login.example.com"},
+			profiles: map[string]map[string]string{"dev": {"base_url": "https://api.dev.example.com"}},
+		},
+		{
+			name:     "environment variable auth url",
+			values:   map[string]interface{}{"environment": "dev"},
+			profiles: map[string]map[string]string{"dev": {"base_url": "https://api.dev.example.com"}},
+			env:      map[string]string{"CUSTOMAPI_AUTH_URL": "https://auth.dev.example.com"},
+		},
+		{
+			name:     "unselected profile without auth url",
+			values:   map[string]interface{}{"environment": "staging"},
+			profiles: map[string]map[string]string{"dev": {"base_url": "https://api.dev.example.com"}},
+		},
+		{
+			name:     "selected profile without auth url",
+			values:   map[string]interface{}{"environment": "dev"},
+			profiles: map[string]map[string]string{"dev": {"base_url": "https://api.dev.example.com"}},
+			wantErr:  true,
+		},
+		{
+			name:         "invalid profile url",
+			values:       map[string]interface{}{"environment": "dev"},
+			profiles:     map[string]map[string]string{"dev": {"auth_url": "not a url"}},
+			wantValidate: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, name := range []string{"CUSTOMAPI_AUTH_URL", "CUSTOMAPI_ISSUER_URL", "CUSTOMAPI_PROFILE", "CUSTOMAPI_ENVIRONMENT"} {
+				t.Setenv(name, tc.env[name])
+			}
+
+			values := map[string]interface{}{
+				"auth_token":   "token",
+				"base_url":     "https://api.example.com",
+				"environments": testEnvironments(t, tc.profiles),
+			}
+			for name, value := range tc.values {
+				values[name] = value
+			}
+			config := testProviderConfig(t, values)
+			p := &CustomAPIProvider{}
+
+			validateResp := &provider.ValidateConfigResponse{}
+			p.ValidateConfig(context.Background(), provider.ValidateConfigRequest{Config: config}, validateResp)
+			if validateResp.Diagnostics.HasError() != tc.wantValidate {
+				t.Fatalf("ValidateConfig HasError = %v, want %v: %v", validateResp.Diagnostics.HasError(), tc.wantValidate, validateResp.Diagnostics)
+			}
+			if tc.wantValidate {
+				return
+			}
+
+			configureResp := &provider.ConfigureResponse{}
+			p.Configure(context.Background(), provider.ConfigureRequest{Config: config}, configureResp)
+			if tc.wantErr && configureResp.Diagnostics.HasError() && configureResp.Diagnostics[0].Summary() != "Missing Auth URL" {
+				t.Errorf("Configure error = %v, want Missing Auth URL", configureResp.Diagnostics)
+			}
+			if configureResp.Diagnostics.HasError() != tc.wantErr {
+				t.Errorf("Configure HasError = %v, want %v: %v", configureResp.Diagnostics.HasError(), tc.wantErr, configureResp.Diagnostics)
+			}
+		})
+	}
+}
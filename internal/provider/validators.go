@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var httpMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+func httpMethodValidator() validator.String {
+	return stringvalidator.OneOfCaseInsensitive(httpMethods...)
+}
+
+func statusCodesValidator() validator.List {
+	return listvalidator.ValueInt64sAre(int64validator.Between(100, 599))
+}
+
+func regularExpressionsValidator() validator.List {
+	return listvalidator.ValueStringsAre(regularExpressionValidator{})
+}
+
+// pathOrURLValidator requires a path relative to base_url starting with "/"
+// or an absolute http(s) URL.
+type pathOrURLValidator struct{}
+
+func (v pathOrURLValidator) Description(ctx context.Context) string {
+	return "value must be a path starting with / or an absolute http(s) URL"
+}
+
+func (v pathOrURLValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v pathOrURLValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if strings.HasPrefix(value, "/") {
+		return
+	}
+
+	parsed, err := url.Parse(value)
+	if err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != "" {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid Endpoint",
+		fmt.Sprintf("Expected a path starting with / or an absolute http(s) URL, got: %q", value),
+	)
+}
+
+// jsonStringValidator requires the value to be a valid JSON document.
+type jsonStringValidator struct{}
+
+func (v jsonStringValidator) Description(ctx context.Context) string {
+	return "value must be valid JSON"
+}
+
+func (v jsonStringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v jsonStringValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if !json.Valid([]byte(req.ConfigValue.ValueString())) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid JSON",
+			"The value must be a valid JSON document, for example produced with jsonencode().",
+		)
+	}
+}
+
+// regularExpressionValidator requires the value to compile as a regular expression.
+type regularExpressionValidator struct{}
+
+func (v regularExpressionValidator) Description(ctx context.Context) string {
+	return "value must be a valid regular expression"
+}
+
+func (v regularExpressionValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v regularExpressionValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := regexp.Compile(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Regular Expression",
+			fmt.Sprintf("%q is not a valid regular expression: %v", req.ConfigValue.ValueString(), err),
+		)
+	}
+}
+
+// patchStrategyPathValidator requires update_path or read_path when
+// update_strategy sends a patch, since a patch cannot be replayed against the
+// create endpoint.
+type patchStrategyPathValidator struct{}
+
+func (v patchStrategyPathValidator) Description(ctx context.Context) string {
+	return "update_path or read_path must be set when update_strategy is not put"
+}
+
+func (v patchStrategyPathValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v patchStrategyPathValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var strategy, updatePath, readPath types.String
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("update_strategy"), &strategy)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("update_path"), &updatePath)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("read_path"), &readPath)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !isKnownString(strategy) || strategy.ValueString() == updateStrategyPut {
+		return
+	}
+
+	if updatePath.IsUnknown() || readPath.IsUnknown() || isKnownString(updatePath) || isKnownString(readPath) {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		path.Root("update_strategy"),
+		"Missing Update Path",
+		fmt.Sprintf("update_strategy %q sends a patch to the existing object, so update_path or read_path must be set.", strategy.ValueString()),
+	)
+}